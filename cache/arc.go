@@ -0,0 +1,161 @@
+package cache
+
+import (
+	gttype "github.com/BeginerAndProgresses/generalized-tools/type"
+)
+
+// arcPolicy 自适应替换缓存（Megiddo & Modha, ARC）
+//   - t1：只访问过一次的元素，t2：访问过至少两次的元素
+//   - b1、b2：最近从 t1、t2 淘汰的 key（幽灵元素），只记录权重
+//   - p：t1 的目标权重，b1 命中说明 t1 太小，增大 p；b2 命中说明 t2 太小，减小 p
+//
+// 原算法按页数计算，这里改为按权重计算，每个元素的权重为 1 时与原算法一致。
+// 链表头部为 LRU 端，尾部为 MRU 端。
+type arcPolicy[K comparable, V any] struct {
+	t1, t2   gttype.LinkedHashMap[K, *entry[K, V]]
+	b1, b2   gttype.LinkedHashMap[K, int64]
+	t1w, t2w int64
+	b1w, b2w int64
+	p        int64
+	capacity int64
+}
+
+func newARC[K comparable, V any](capacity int64) *arcPolicy[K, V] {
+	return &arcPolicy[K, V]{
+		t1:       gttype.NewLinkedHashMap[K, *entry[K, V]](),
+		t2:       gttype.NewLinkedHashMap[K, *entry[K, V]](gttype.WithAccessOrder()),
+		b1:       gttype.NewLinkedHashMap[K, int64](),
+		b2:       gttype.NewLinkedHashMap[K, int64](),
+		capacity: capacity,
+	}
+}
+
+func (p *arcPolicy[K, V]) get(key K) (*entry[K, V], bool) {
+	if e, ok := p.t1.Peek(key); ok {
+		// 第二次访问，从 t1 移到 t2 的 MRU 端
+		p.t1.Remove(key)
+		p.t1w -= e.weight
+		p.t2.Put(key, e)
+		p.t2w += e.weight
+		return e, true
+	}
+	// t2 按访问顺序排列，Get 会把元素移到 MRU 端
+	return p.t2.Get(key)
+}
+
+func (p *arcPolicy[K, V]) peek(key K) (*entry[K, V], bool) {
+	if e, ok := p.t1.Peek(key); ok {
+		return e, true
+	}
+	return p.t2.Peek(key)
+}
+
+func (p *arcPolicy[K, V]) add(e *entry[K, V], evict func(*entry[K, V])) {
+	w, c := e.weight, p.capacity
+	if gw, ok := p.b1.Get(e.key); ok {
+		// b1 命中：增大 t1 的目标权重
+		p.p = min(p.p+max(p.b2w/max(p.b1w, 1), 1)*w, c)
+		p.b1.Remove(e.key)
+		p.b1w -= gw
+		p.makeRoom(w, false, evict)
+		p.t2.Put(e.key, e)
+		p.t2w += w
+		return
+	}
+	if gw, ok := p.b2.Get(e.key); ok {
+		// b2 命中：减小 t1 的目标权重
+		p.p = max(p.p-max(p.b1w/max(p.b2w, 1), 1)*w, 0)
+		p.b2.Remove(e.key)
+		p.b2w -= gw
+		p.makeRoom(w, true, evict)
+		p.t2.Put(e.key, e)
+		p.t2w += w
+		return
+	}
+
+	// 完全未命中：t1+b1 不超过 c，四个链表合计不超过 2c
+	for p.t1w+p.b1w+w > c && p.b1.Len() > 0 {
+		_, gw, _ := p.b1.RemoveEldest()
+		p.b1w -= gw
+	}
+	for p.t1w+w > c {
+		_, victim, _ := p.t1.RemoveEldest()
+		p.t1w -= victim.weight
+		evict(victim)
+	}
+	for p.t1w+p.t2w+p.b1w+p.b2w+w > 2*c && p.b2.Len() > 0 {
+		_, gw, _ := p.b2.RemoveEldest()
+		p.b2w -= gw
+	}
+	p.makeRoom(w, false, evict)
+	p.t1.Put(e.key, e)
+	p.t1w += w
+}
+
+// makeRoom 不断执行 REPLACE，直到 t1、t2 能再容纳权重 w
+func (p *arcPolicy[K, V]) makeRoom(w int64, inB2 bool, evict func(*entry[K, V])) {
+	for p.t1w+p.t2w+w > p.capacity {
+		p.replace(inB2, evict)
+	}
+}
+
+// replace 根据目标权重 p 从 t1 或 t2 淘汰一个元素，并记入对应的幽灵链表
+func (p *arcPolicy[K, V]) replace(inB2 bool, evict func(*entry[K, V])) {
+	if p.t1.Len() > 0 && (p.t1w > p.p || (inB2 && p.t1w == p.p) || p.t2.Len() == 0) {
+		key, victim, _ := p.t1.RemoveEldest()
+		p.t1w -= victim.weight
+		p.b1.Put(key, victim.weight)
+		p.b1w += victim.weight
+		evict(victim)
+		return
+	}
+	key, victim, _ := p.t2.RemoveEldest()
+	p.t2w -= victim.weight
+	p.b2.Put(key, victim.weight)
+	p.b2w += victim.weight
+	evict(victim)
+}
+
+// forget 删除 key 的幽灵记录
+func (p *arcPolicy[K, V]) forget(key K) {
+	if gw, ok := p.b1.Peek(key); ok {
+		p.b1.Remove(key)
+		p.b1w -= gw
+	}
+	if gw, ok := p.b2.Peek(key); ok {
+		p.b2.Remove(key)
+		p.b2w -= gw
+	}
+}
+
+func (p *arcPolicy[K, V]) remove(key K) (*entry[K, V], bool) {
+	p.forget(key)
+	if e, ok := p.t1.Peek(key); ok {
+		p.t1.Remove(key)
+		p.t1w -= e.weight
+		return e, true
+	}
+	if e, ok := p.t2.Peek(key); ok {
+		p.t2.Remove(key)
+		p.t2w -= e.weight
+		return e, true
+	}
+	return nil, false
+}
+
+func (p *arcPolicy[K, V]) len() int {
+	return p.t1.Len() + p.t2.Len()
+}
+
+func (p *arcPolicy[K, V]) weight() int64 {
+	return p.t1w + p.t2w
+}
+
+func (p *arcPolicy[K, V]) clear() {
+	p.t1.Clear()
+	p.t2.Clear()
+	p.b1.Clear()
+	p.b2.Clear()
+	p.t1w, p.t2w, p.b1w, p.b2w = 0, 0, 0, 0
+	p.p = 0
+}
@@ -0,0 +1,322 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// Policy 淘汰策略
+type Policy int
+
+const (
+	LRU Policy = iota // 淘汰最久未访问的元素
+	LFU               // 淘汰访问次数最少的元素，访问次数会定期衰减
+	ARC               // 自适应替换缓存，在最近访问与访问频率之间自动平衡
+)
+
+// ErrInvalidCapacity 容量不大于 0
+var ErrInvalidCapacity = errors.New("cache: 容量必须大于 0")
+
+// Cache 有界的并发安全缓存
+// 容量默认按元素个数计算，设置 WithWeigher 后按权重（例如字节数）计算。
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	// Peek 获取 key 对应的值，不更新访问记录和统计
+	Peek(key K) (V, bool)
+	// Set 写入键值对，权重超过容量的元素不会被写入
+	Set(key K, val V)
+	// GetOrLoad key 不存在时调用 loader 加载并写入缓存
+	// 同一个 key 的并发加载只会调用一次 loader，其余调用等待并共享结果
+	// loader panic 时不写入缓存，调用方和等待中的调用都会重新 panic
+	// 加载期间 key 被 Set、Remove 或 Clear 时，加载的结果只返回给调用方，不写入缓存
+	GetOrLoad(key K, loader func(key K) (V, error)) (V, error)
+	Remove(key K) bool
+	Len() int
+	// Weight 当前所有元素的权重之和
+	Weight() int64
+	Stats() Stats
+	Clear()
+}
+
+// Stats 缓存统计
+type Stats struct {
+	Hits       uint64 // 命中次数
+	Misses     uint64 // 未命中次数
+	Evictions  uint64 // 因容量不足被淘汰的元素个数
+	Loads      uint64 // loader 成功加载的次数
+	LoadErrors uint64 // loader 返回错误的次数
+}
+
+// HitRate 命中率
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Option 缓存的可选配置
+type Option[K comparable, V any] func(*config[K, V])
+
+type config[K comparable, V any] struct {
+	weigher func(key K, val V) int64
+	onEvict func(key K, val V)
+}
+
+// WithWeigher 设置元素的权重函数，容量按权重之和计算
+func WithWeigher[K comparable, V any](weigher func(key K, val V) int64) Option[K, V] {
+	return func(c *config[K, V]) {
+		c.weigher = weigher
+	}
+}
+
+// WithEvictCallback 设置元素因容量不足被淘汰时的回调，回调期间持有缓存的锁
+func WithEvictCallback[K comparable, V any](fn func(key K, val V)) Option[K, V] {
+	return func(c *config[K, V]) {
+		c.onEvict = fn
+	}
+}
+
+type entry[K comparable, V any] struct {
+	key    K
+	val    V
+	weight int64
+
+	// LFU 使用
+	freq  uint64
+	tick  uint64
+	index int
+}
+
+// policy 淘汰策略的实现，所有方法都在缓存的锁内调用
+type policy[K comparable, V any] interface {
+	// get 查找 key 并记录一次访问
+	get(key K) (*entry[K, V], bool)
+	// peek 查找 key，不记录访问
+	peek(key K) (*entry[K, V], bool)
+	// add 写入一个新的元素，权重之和超过容量时通过 evict 回调淘汰元素
+	// 调用方保证 key 不存在且元素权重不超过容量
+	add(e *entry[K, V], evict func(*entry[K, V]))
+	remove(key K) (*entry[K, V], bool)
+	len() int
+	weight() int64
+	clear()
+}
+
+type adkCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	policy   policy[K, V]
+	capacity int64
+	cfg      config[K, V]
+	stats    Stats
+	loading  map[K]*loadCall[V]
+}
+
+type loadCall[V any] struct {
+	wg  sync.WaitGroup
+	val V
+	err error
+	// stale 加载期间 key 被修改过，结果不再写入缓存，在缓存的锁内读写
+	stale bool
+}
+
+// errLoaderGoexit loader 调用了 runtime.Goexit，等待中的调用返回该错误
+var errLoaderGoexit = errors.New("cache: loader 调用了 runtime.Goexit")
+
+// panicError 保存 loader panic 的值和调用栈，在每个调用方重新 panic
+type panicError struct {
+	value any
+	stack []byte
+}
+
+func (p *panicError) Error() string {
+	return fmt.Sprintf("cache: loader panic: %v\n\n%s", p.value, p.stack)
+}
+
+func (p *panicError) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+// New 创建一个容量为 capacity 的缓存
+// capacity 不大于 0 时返回 nil 与 ErrInvalidCapacity
+func New[K comparable, V any](capacity int64, p Policy, opts ...Option[K, V]) (Cache[K, V], error) {
+	if capacity <= 0 {
+		return nil, ErrInvalidCapacity
+	}
+	c := &adkCache[K, V]{capacity: capacity, loading: make(map[K]*loadCall[V])}
+	for _, opt := range opts {
+		opt(&c.cfg)
+	}
+	switch p {
+	case LFU:
+		c.policy = newLFU[K, V](capacity)
+	case ARC:
+		c.policy = newARC[K, V](capacity)
+	default:
+		c.policy = newLRU[K, V](capacity)
+	}
+	return c, nil
+}
+
+func (c *adkCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.policy.get(key); ok {
+		c.stats.Hits++
+		return e.val, true
+	}
+	c.stats.Misses++
+	var zero V
+	return zero, false
+}
+
+func (c *adkCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.policy.peek(key); ok {
+		return e.val, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (c *adkCache[K, V]) Set(key K, val V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(key)
+	c.set(key, val)
+}
+
+func (c *adkCache[K, V]) set(key K, val V) {
+	weight := int64(1)
+	if c.cfg.weigher != nil {
+		weight = c.cfg.weigher(key, val)
+	}
+	if weight > c.capacity {
+		// 单个元素超过容量，不写入，同时删除旧值以免读到过期的数据
+		c.policy.remove(key)
+		return
+	}
+	if e, ok := c.policy.get(key); ok {
+		e.val = val
+		if e.weight == weight {
+			return
+		}
+		// 权重变化时重新写入，以便按新的权重淘汰
+		c.policy.remove(key)
+	}
+	c.policy.add(&entry[K, V]{key: key, val: val, weight: weight}, c.evict)
+}
+
+func (c *adkCache[K, V]) evict(e *entry[K, V]) {
+	c.stats.Evictions++
+	if c.cfg.onEvict != nil {
+		c.cfg.onEvict(e.key, e.val)
+	}
+}
+
+func (c *adkCache[K, V]) GetOrLoad(key K, loader func(key K) (V, error)) (V, error) {
+	c.mu.Lock()
+	if e, ok := c.policy.get(key); ok {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return e.val, nil
+	}
+	c.stats.Misses++
+	if call, ok := c.loading[key]; ok {
+		c.mu.Unlock()
+		call.wg.Wait()
+		return call.result()
+	}
+	call := &loadCall[V]{}
+	call.wg.Add(1)
+	c.loading[key] = call
+	c.mu.Unlock()
+
+	c.load(key, call, loader)
+	return call.result()
+}
+
+// load 调用 loader 并记录结果，loader panic 或 Goexit 时也会清理 loading 并唤醒等待的调用
+func (c *adkCache[K, V]) load(key K, call *loadCall[V], loader func(key K) (V, error)) {
+	returned := false
+	defer func() {
+		if !returned {
+			if r := recover(); r != nil {
+				call.err = &panicError{value: r, stack: debug.Stack()}
+			} else {
+				call.err = errLoaderGoexit
+			}
+		}
+
+		c.mu.Lock()
+		delete(c.loading, key)
+		if call.err == nil {
+			c.stats.Loads++
+			if !call.stale {
+				c.set(key, call.val)
+			}
+		} else {
+			c.stats.LoadErrors++
+		}
+		c.mu.Unlock()
+		call.wg.Done()
+	}()
+
+	call.val, call.err = loader(key)
+	returned = true
+}
+
+// invalidate 使 key 正在进行的加载不再写入缓存，以免旧的结果覆盖之后的修改
+func (c *adkCache[K, V]) invalidate(key K) {
+	if call, ok := c.loading[key]; ok {
+		call.stale = true
+	}
+}
+
+// result 返回加载的结果，loader panic 时重新 panic
+func (call *loadCall[V]) result() (V, error) {
+	if p, ok := call.err.(*panicError); ok {
+		panic(p)
+	}
+	return call.val, call.err
+}
+
+func (c *adkCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(key)
+	_, ok := c.policy.remove(key)
+	return ok
+}
+
+func (c *adkCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.policy.len()
+}
+
+func (c *adkCache[K, V]) Weight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.policy.weight()
+}
+
+func (c *adkCache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *adkCache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, call := range c.loading {
+		call.stale = true
+	}
+	c.policy.clear()
+}
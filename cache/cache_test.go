@@ -0,0 +1,299 @@
+package cache
+
+import (
+	"errors"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var policies = []struct {
+	name   string
+	policy Policy
+}{
+	{"LRU", LRU},
+	{"LFU", LFU},
+	{"ARC", ARC},
+}
+
+func TestNew_InvalidCapacity(t *testing.T) {
+	if _, err := New[int, int](0, LRU); !errors.Is(err, ErrInvalidCapacity) {
+		t.Fatalf("New(0) err = %v, want ErrInvalidCapacity", err)
+	}
+}
+
+func TestCache_LRU(t *testing.T) {
+	var evicted []int
+	c, _ := New[int, int](3, LRU, WithEvictCallback(func(key, _ int) {
+		evicted = append(evicted, key)
+	}))
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Get(1)
+	c.Set(4, 4) // 淘汰 2
+	c.Set(5, 5) // 淘汰 3
+	if len(evicted) != 2 || evicted[0] != 2 || evicted[1] != 3 {
+		t.Fatalf("淘汰顺序 = %v, want [2 3]", evicted)
+	}
+	if _, ok := c.Get(2); ok {
+		t.Fatal("2 应已被淘汰")
+	}
+	if v, ok := c.Peek(1); !ok || v != 1 {
+		t.Fatalf("Peek(1) = %v, %v", v, ok)
+	}
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 2 {
+		t.Fatalf("Stats = %+v", s)
+	}
+}
+
+func TestCache_LFU(t *testing.T) {
+	c, _ := New[int, int](3, LFU)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	for i := 0; i < 5; i++ {
+		c.Get(1)
+		c.Get(2)
+	}
+	c.Set(4, 4) // 3 的访问次数最少
+	if _, ok := c.Peek(3); ok {
+		t.Fatal("访问次数最少的 3 应被淘汰")
+	}
+	c.Set(5, 5) // 4 与 5 频率相同，淘汰较早访问的 4
+	if _, ok := c.Peek(4); ok {
+		t.Fatal("频率相同时应淘汰较早访问的 4")
+	}
+
+	// 持续访问新的 key，衰减之后旧的热点最终会被淘汰
+	for i := 0; i < 200; i++ {
+		c.Get(5)
+		c.Get(5)
+	}
+	c.Set(6, 6)
+	c.Get(6)
+	c.Set(7, 7)
+	_, ok1 := c.Peek(1)
+	_, ok2 := c.Peek(2)
+	if ok1 || ok2 {
+		t.Fatal("频率衰减后旧的热点应被淘汰")
+	}
+}
+
+func TestCache_ARC_ScanResistant(t *testing.T) {
+	c, _ := New[int, int](100, ARC)
+	// 热点数据访问两次，进入 t2
+	for i := 0; i < 50; i++ {
+		c.Set(i, i)
+		c.Get(i)
+	}
+	// 一次性扫描大量新数据
+	for i := 1000; i < 2000; i++ {
+		c.Set(i, i)
+	}
+	for i := 0; i < 50; i++ {
+		if _, ok := c.Peek(i); !ok {
+			t.Fatalf("扫描之后热点 %d 被淘汰", i)
+		}
+	}
+	if c.Len() != 100 {
+		t.Fatalf("Len = %d, want 100", c.Len())
+	}
+}
+
+func TestCache_Weigher(t *testing.T) {
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			c, _ := New[string, []byte](10, p.policy, WithWeigher(func(key string, val []byte) int64 {
+				return int64(len(val))
+			}))
+			c.Set("a", make([]byte, 4))
+			c.Set("b", make([]byte, 4))
+			c.Set("c", make([]byte, 4))
+			if c.Weight() > 10 || c.Len() != 2 {
+				t.Fatalf("Weight = %d, Len = %d", c.Weight(), c.Len())
+			}
+			c.Set("big", make([]byte, 11))
+			if _, ok := c.Peek("big"); ok || c.Len() != 2 {
+				t.Fatal("超过容量的元素不应被写入，也不应淘汰其他元素")
+			}
+			c.Set("c", make([]byte, 8))
+			if c.Weight() != 8 || c.Len() != 1 {
+				t.Fatalf("改变权重后 Weight = %d, Len = %d", c.Weight(), c.Len())
+			}
+		})
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	c, _ := New[string, int](10, LRU)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(key string) (int, error) {
+		calls.Add(1)
+		<-release
+		return len(key), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.GetOrLoad("hello", loader)
+		}(i)
+	}
+	// 等第一个加载开始后再放行
+	for calls.Load() == 0 {
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("loader 被调用 %d 次, want 1", calls.Load())
+	}
+	for _, v := range results {
+		if v != 5 {
+			t.Fatalf("GetOrLoad = %d, want 5", v)
+		}
+	}
+	if s := c.Stats(); s.Loads != 1 {
+		t.Fatalf("Stats = %+v", s)
+	}
+
+	errLoad := errors.New("load failed")
+	if _, err := c.GetOrLoad("x", func(string) (int, error) { return 0, errLoad }); !errors.Is(err, errLoad) {
+		t.Fatalf("GetOrLoad err = %v", err)
+	}
+	if _, ok := c.Peek("x"); ok {
+		t.Fatal("加载失败时不应写入缓存")
+	}
+	if s := c.Stats(); s.LoadErrors != 1 {
+		t.Fatalf("Stats = %+v", s)
+	}
+}
+
+func TestCache_GetOrLoadStale(t *testing.T) {
+	c, _ := New[string, int](10, LRU)
+	for _, modify := range []func(){
+		func() { c.Set("k", 100) },
+		func() { c.Remove("k") },
+		c.Clear,
+	} {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan int)
+		go func() {
+			v, _ := c.GetOrLoad("k", func(string) (int, error) {
+				close(started)
+				<-release
+				return 1, nil
+			})
+			done <- v
+		}()
+		<-started
+		modify()
+		want, wantOK := c.Peek("k")
+		close(release)
+		if v := <-done; v != 1 {
+			t.Fatalf("GetOrLoad = %d, want 1", v)
+		}
+		if v, ok := c.Peek("k"); v != want || ok != wantOK {
+			t.Fatalf("加载期间修改过的 key 被覆盖: Peek = %d, %v, want %d, %v", v, ok, want, wantOK)
+		}
+		c.Remove("k")
+	}
+}
+
+func TestCache_Random(t *testing.T) {
+	for _, p := range policies {
+		t.Run(p.name, func(t *testing.T) {
+			c, _ := New[int, int](64, p.policy, WithWeigher(func(key, _ int) int64 {
+				return int64(key%4 + 1)
+			}))
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 20000; i++ {
+				key := rnd.Intn(200)
+				switch rnd.Intn(4) {
+				case 0:
+					c.Remove(key)
+				case 1:
+					c.Get(key)
+				default:
+					c.Set(key, key)
+				}
+				if c.Weight() > 64 {
+					t.Fatalf("Weight = %d 超过容量", c.Weight())
+				}
+			}
+			var weight int64
+			for key := 0; key < 200; key++ {
+				if v, ok := c.Peek(key); ok {
+					if v != key {
+						t.Fatalf("Peek(%d) = %d", key, v)
+					}
+					weight += int64(key%4 + 1)
+				}
+			}
+			if weight != c.Weight() {
+				t.Fatalf("Weight = %d, 实际 %d", c.Weight(), weight)
+			}
+			c.Clear()
+			if c.Len() != 0 || c.Weight() != 0 {
+				t.Fatal("Clear 之后应为空")
+			}
+		})
+	}
+}
+
+func TestCache_GetOrLoadPanic(t *testing.T) {
+	c, _ := New[string, int](10, LRU)
+	var once sync.Once
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(string) (int, error) {
+		once.Do(func() { close(started) })
+		<-release
+		panic("boom")
+	}
+	mustPanic := func(fn func()) (r any) {
+		defer func() { r = recover() }()
+		fn()
+		return nil
+	}
+
+	// 等待中的调用也会 panic；来晚了自己调用 loader 时同样 panic
+	waiter := make(chan any)
+	go func() {
+		<-started
+		waiter <- mustPanic(func() { c.GetOrLoad("k", loader) })
+	}()
+	go func() {
+		<-started
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+
+	r := mustPanic(func() { c.GetOrLoad("k", loader) })
+	if p, ok := r.(*panicError); !ok || p.value != "boom" {
+		t.Fatalf("recover = %v, want loader 的 panic", r)
+	}
+	if r := <-waiter; r == nil {
+		t.Fatal("等待中的调用没有 panic")
+	}
+
+	// panic 之后同一个 key 仍然可以加载，不会永久阻塞
+	v, err := c.GetOrLoad("k", func(string) (int, error) { return 7, nil })
+	if err != nil || v != 7 {
+		t.Fatalf("GetOrLoad = %d, %v", v, err)
+	}
+	if s := c.Stats(); s.Loads != 1 || s.LoadErrors == 0 {
+		t.Fatalf("Stats = %+v", s)
+	}
+}
@@ -0,0 +1,139 @@
+package cache
+
+import "container/heap"
+
+// lfuAgingFactor 访问次数累计达到元素个数的若干倍后，所有频率减半
+// 这样曾经很热但已经不再访问的元素最终也会被淘汰
+const (
+	lfuAgingFactor  = 10
+	lfuAgingMinimum = 64
+)
+
+// lfuPolicy 按 (频率, 最近访问时刻) 排序的小顶堆，堆顶即淘汰对象
+// 频率相同时淘汰最久未访问的元素
+type lfuPolicy[K comparable, V any] struct {
+	items    map[K]*entry[K, V]
+	heap     lfuHeap[K, V]
+	capacity int64
+	total    int64
+	tick     uint64 // 逻辑时钟，每次访问加一
+	accesses int    // 上次衰减之后的访问次数
+}
+
+func newLFU[K comparable, V any](capacity int64) *lfuPolicy[K, V] {
+	return &lfuPolicy[K, V]{
+		items:    make(map[K]*entry[K, V]),
+		capacity: capacity,
+	}
+}
+
+func (p *lfuPolicy[K, V]) touch(e *entry[K, V]) {
+	p.tick++
+	e.tick = p.tick
+	e.freq++
+	heap.Fix(&p.heap, e.index)
+	p.accesses++
+	if limit := max(len(p.items)*lfuAgingFactor, lfuAgingMinimum); p.accesses >= limit {
+		p.age()
+	}
+}
+
+// age 所有频率减半并重建堆
+func (p *lfuPolicy[K, V]) age() {
+	for _, e := range p.heap {
+		e.freq >>= 1
+	}
+	heap.Init(&p.heap)
+	p.accesses = 0
+}
+
+func (p *lfuPolicy[K, V]) get(key K) (*entry[K, V], bool) {
+	e, ok := p.items[key]
+	if ok {
+		p.touch(e)
+	}
+	return e, ok
+}
+
+func (p *lfuPolicy[K, V]) peek(key K) (*entry[K, V], bool) {
+	e, ok := p.items[key]
+	return e, ok
+}
+
+func (p *lfuPolicy[K, V]) add(e *entry[K, V], evict func(*entry[K, V])) {
+	// 先腾出空间再写入，避免新元素因频率最低被立即淘汰
+	for p.total+e.weight > p.capacity && p.heap.Len() > 0 {
+		victim := heap.Pop(&p.heap).(*entry[K, V])
+		delete(p.items, victim.key)
+		p.total -= victim.weight
+		evict(victim)
+	}
+	p.tick++
+	e.tick = p.tick
+	e.freq = 1
+	p.items[e.key] = e
+	heap.Push(&p.heap, e)
+	p.total += e.weight
+}
+
+func (p *lfuPolicy[K, V]) remove(key K) (*entry[K, V], bool) {
+	e, ok := p.items[key]
+	if !ok {
+		return nil, false
+	}
+	delete(p.items, key)
+	heap.Remove(&p.heap, e.index)
+	p.total -= e.weight
+	return e, true
+}
+
+func (p *lfuPolicy[K, V]) len() int {
+	return len(p.items)
+}
+
+func (p *lfuPolicy[K, V]) weight() int64 {
+	return p.total
+}
+
+func (p *lfuPolicy[K, V]) clear() {
+	clear(p.items)
+	p.heap = nil
+	p.total = 0
+	p.accesses = 0
+}
+
+// lfuHeap 实现 heap.Interface，元素记录自身在堆中的下标以支持 Fix 和 Remove
+type lfuHeap[K comparable, V any] []*entry[K, V]
+
+func (h lfuHeap[K, V]) Len() int {
+	return len(h)
+}
+
+func (h lfuHeap[K, V]) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].tick < h[j].tick
+}
+
+func (h lfuHeap[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *lfuHeap[K, V]) Push(x any) {
+	e := x.(*entry[K, V])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *lfuHeap[K, V]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*h = old[:n-1]
+	return e
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	gttype "github.com/BeginerAndProgresses/generalized-tools/type"
+)
+
+// lruPolicy 基于访问顺序的 LinkedHashMap，链表头部即最久未访问的元素
+type lruPolicy[K comparable, V any] struct {
+	items    gttype.LinkedHashMap[K, *entry[K, V]]
+	capacity int64
+	total    int64
+}
+
+func newLRU[K comparable, V any](capacity int64) *lruPolicy[K, V] {
+	return &lruPolicy[K, V]{
+		items:    gttype.NewLinkedHashMap[K, *entry[K, V]](gttype.WithAccessOrder()),
+		capacity: capacity,
+	}
+}
+
+func (p *lruPolicy[K, V]) get(key K) (*entry[K, V], bool) {
+	return p.items.Get(key)
+}
+
+func (p *lruPolicy[K, V]) peek(key K) (*entry[K, V], bool) {
+	return p.items.Peek(key)
+}
+
+func (p *lruPolicy[K, V]) add(e *entry[K, V], evict func(*entry[K, V])) {
+	for p.total+e.weight > p.capacity {
+		_, victim, ok := p.items.RemoveEldest()
+		if !ok {
+			break
+		}
+		p.total -= victim.weight
+		evict(victim)
+	}
+	p.items.Put(e.key, e)
+	p.total += e.weight
+}
+
+func (p *lruPolicy[K, V]) remove(key K) (*entry[K, V], bool) {
+	e, ok := p.items.Peek(key)
+	if !ok {
+		return nil, false
+	}
+	p.items.Remove(key)
+	p.total -= e.weight
+	return e, true
+}
+
+func (p *lruPolicy[K, V]) len() int {
+	return p.items.Len()
+}
+
+func (p *lruPolicy[K, V]) weight() int64 {
+	return p.total
+}
+
+func (p *lruPolicy[K, V]) clear() {
+	p.items.Clear()
+	p.total = 0
+}
@@ -1,6 +1,6 @@
 module github.com/BeginerAndProgresses/generalized-tools
 
-go 1.22.5
+go 1.24
 
 require github.com/stretchr/testify v1.9.0
 
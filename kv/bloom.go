@@ -0,0 +1,64 @@
+package kv
+
+import "math"
+
+// bloom 布隆过滤器，格式为 k（1 字节）加上位数组
+// 使用 FNV-1a 哈希及其循环移位做双重哈希，结果与进程无关，可以持久化。
+type bloom []byte
+
+// bloomHash FNV-1a 64 位哈希
+func bloomHash(key string) uint64 {
+	h := uint64(14695981039346656037)
+
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+
+	return h
+}
+
+// newBloom 根据 key 的哈希值和每个 key 的位数构建布隆过滤器
+func newBloom(hashes []uint64, bitsPerKey int) bloom {
+	k := int(math.Round(float64(bitsPerKey) * math.Ln2))
+	k = min(max(k, 1), 30)
+
+	bits := max(len(hashes)*bitsPerKey, 64)
+	filter := make(bloom, (bits+7)/8+1)
+	filter[0] = byte(k)
+	bits = (len(filter) - 1) * 8
+
+	for _, h := range hashes {
+		delta := h>>33 | h<<31
+
+		for i := 0; i < k; i++ {
+			pos := h % uint64(bits)
+			filter[1+pos/8] |= 1 << (pos % 8)
+			h += delta
+		}
+	}
+
+	return filter
+}
+
+// mayContain 返回 key 是否可能存在，返回 false 时一定不存在
+func (b bloom) mayContain(key string) bool {
+	if len(b) < 2 {
+		return true
+	}
+
+	k := int(b[0])
+	bits := uint64(len(b)-1) * 8
+	h := bloomHash(key)
+	delta := h>>33 | h<<31
+
+	for i := 0; i < k; i++ {
+		pos := h % bits
+		if b[1+pos/8]&(1<<(pos%8)) == 0 {
+			return false
+		}
+		h += delta
+	}
+
+	return true
+}
@@ -0,0 +1,341 @@
+package kv
+
+import (
+	"container/heap"
+	"os"
+	"slices"
+	"strings"
+)
+
+// mergeIter 多路归并多个 iterator，同一个 key 只返回优先级最高（下标最小）的 iterator 中的 record
+type mergeIter struct {
+	h    mergeHeap
+	curr record
+	e    error
+}
+
+type mergeSource struct {
+	it       iterator
+	priority int
+}
+
+type mergeHeap []mergeSource
+
+func (h mergeHeap) Len() int { return len(h) }
+
+func (h mergeHeap) Less(i, j int) bool {
+	if c := strings.Compare(h[i].it.record().key, h[j].it.record().key); c != 0 {
+		return c < 0
+	}
+
+	return h[i].priority < h[j].priority
+}
+
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(x any) { *h = append(*h, x.(mergeSource)) }
+
+func (h *mergeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// newMergeIter its 按优先级从高到低排列，越新的数据优先级越高
+func newMergeIter(its []iterator) *mergeIter {
+	m := &mergeIter{}
+
+	for i, it := range its {
+		m.push(mergeSource{it: it, priority: i})
+	}
+
+	heap.Init(&m.h)
+	return m
+}
+
+// push 前进 source 并在还有数据时放回堆中
+func (m *mergeIter) push(s mergeSource) {
+	if s.it.next() {
+		m.h = append(m.h, s)
+	} else if err := s.it.err(); err != nil && m.e == nil {
+		m.e = err
+	}
+}
+
+func (m *mergeIter) next() bool {
+	if m.e != nil || len(m.h) == 0 {
+		return false
+	}
+
+	m.curr = m.h[0].it.record()
+
+	// 跳过所有 iterator 中相同的 key
+	for len(m.h) > 0 && m.h[0].it.record().key == m.curr.key {
+		s := heap.Pop(&m.h).(mergeSource)
+
+		if s.it.next() {
+			heap.Push(&m.h, s)
+		} else if err := s.it.err(); err != nil {
+			m.e = err
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *mergeIter) record() record {
+	return m.curr
+}
+
+func (m *mergeIter) err() error {
+	return m.e
+}
+
+// maxBytesForLevel 第 level 层（level >= 1）的容量，每一层是上一层的 levelMultiplier 倍
+func (db *DB) maxBytesForLevel(level int) int64 {
+	size := db.cfg.levelSizeBase
+
+	for i := 1; i < level; i++ {
+		size *= levelMultiplier
+	}
+
+	return size
+}
+
+func levelSize(tables []*table) (size int64) {
+	for _, t := range tables {
+		size += t.size
+	}
+
+	return
+}
+
+// keyRange 返回 tables 的最小 key 和最大 key
+func keyRange(tables []*table) (smallest, largest string) {
+	for i, t := range tables {
+		if i == 0 || t.smallest < smallest {
+			smallest = t.smallest
+		}
+
+		if i == 0 || t.largest > largest {
+			largest = t.largest
+		}
+	}
+
+	return
+}
+
+// overlapping 返回 level 层中与 [smallest, largest] 相交的 table
+func (db *DB) overlapping(level int, smallest, largest string) []*table {
+	var result []*table
+
+	for _, t := range db.levels[level] {
+		if t.overlaps(smallest, largest) {
+			result = append(result, t)
+		}
+	}
+
+	return result
+}
+
+// pickCompaction 选择需要合并的层和该层的输入，没有需要合并的层时返回 -1
+// 第 0 层的 table 个数达到阈值时合并第 0 层的所有 table；
+// 其他层超过容量时，从上次合并的位置之后选择一个 table，轮流合并整层的 key。
+func (db *DB) pickCompaction() (int, []*table) {
+	if len(db.levels[0]) >= db.cfg.l0CompactionTrigger {
+		return 0, slices.Clone(db.levels[0])
+	}
+
+	for level := 1; level < numLevels-1; level++ {
+		tables := db.levels[level]
+
+		if levelSize(tables) <= db.maxBytesForLevel(level) {
+			continue
+		}
+
+		for _, t := range tables {
+			if t.smallest > db.compactPointer[level] {
+				return level, []*table{t}
+			}
+		}
+
+		return level, []*table{tables[0]}
+	}
+
+	return -1, nil
+}
+
+// compactLocked 合并到所有层都不超过限制为止，调用时必须持有写锁
+func (db *DB) compactLocked() error {
+	for {
+		level, inputs := db.pickCompaction()
+
+		// 没有输入时合并不会改变任何一层，继续循环只会重复选中同一层
+		if level < 0 || len(inputs) == 0 {
+			return nil
+		}
+
+		if err := db.compact(level, inputs); err != nil {
+			return err
+		}
+	}
+}
+
+// compact 把 level 层的 inputs 与 level+1 层中相交的 table 合并，写入 level+1 层
+func (db *DB) compact(level int, inputs []*table) error {
+	smallest, largest := keyRange(inputs)
+	next := db.overlapping(level+1, smallest, largest)
+
+	// 更深的层中没有相交的 key 时，删除标记已经没有需要遮盖的数据，可以丢弃
+	// 输出的范围包括 level+1 层的 table，可能比 inputs 更宽
+	all := append(slices.Clone(inputs), next...)
+	outSmallest, outLargest := keyRange(all)
+	dropDeleted := true
+
+	for l := level + 2; l < numLevels; l++ {
+		if len(db.overlapping(l, outSmallest, outLargest)) > 0 {
+			dropDeleted = false
+			break
+		}
+	}
+
+	// 第 0 层中越新的 table 优先级越高，level+1 层的数据最旧
+	var its []iterator
+
+	if level == 0 {
+		for i := len(inputs) - 1; i >= 0; i-- {
+			its = append(its, inputs[i].iter(""))
+		}
+	} else {
+		its = append(its, inputs[0].iter(""))
+	}
+
+	for _, t := range next {
+		its = append(its, t.iter(""))
+	}
+
+	outputs, err := db.writeTables(newMergeIter(its), dropDeleted)
+	if err != nil {
+		return err
+	}
+
+	removed := make(map[*table]bool, len(all))
+	for _, t := range all {
+		removed[t] = true
+	}
+
+	keep := func(tables []*table) []*table {
+		return slices.DeleteFunc(slices.Clone(tables), func(t *table) bool {
+			return removed[t]
+		})
+	}
+
+	levels := db.levels
+	levels[level] = keep(levels[level])
+	levels[level+1] = append(keep(levels[level+1]), outputs...)
+	slices.SortFunc(levels[level+1], func(a, b *table) int {
+		return strings.Compare(a.smallest, b.smallest)
+	})
+
+	if err = db.saveManifest(levels, db.logNum); err != nil {
+		for _, t := range outputs {
+			t.obsolete.Store(true)
+			t.unref()
+		}
+
+		return err
+	}
+
+	db.levels = levels
+
+	// 合并位置只按 inputs 前进，否则会跳过 level 层中落在 level+1 层范围内的 table
+	if level > 0 {
+		db.compactPointer[level] = largest
+	}
+
+	for _, t := range all {
+		t.obsolete.Store(true)
+		t.unref()
+	}
+
+	return nil
+}
+
+// writeTables 把 it 中的数据写入新的 SSTable，每个文件超过 tableFileSize 后切换到下一个文件
+func (db *DB) writeTables(it iterator, dropDeleted bool) (outputs []*table, err error) {
+	var tw *tableWriter
+	var num uint64
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if tw != nil {
+			tw.abort()
+		}
+
+		for _, t := range outputs {
+			t.obsolete.Store(true)
+			t.unref()
+		}
+
+		outputs = nil
+	}()
+
+	finish := func() error {
+		err := tw.finish()
+		tw = nil
+
+		if err != nil {
+			os.Remove(fileName(db.dir, num, tableExt))
+			return err
+		}
+
+		t, err := openTable(fileName(db.dir, num, tableExt), num)
+		if err != nil {
+			return err
+		}
+
+		outputs = append(outputs, t)
+		return nil
+	}
+
+	for it.next() {
+		r := it.record()
+
+		if r.deleted && dropDeleted {
+			continue
+		}
+
+		if tw == nil {
+			num = db.newFileNum()
+
+			if tw, err = newTableWriter(fileName(db.dir, num, tableExt), db.cfg.blockSize, db.cfg.bloomBitsPerKey); err != nil {
+				return
+			}
+		}
+
+		if err = tw.add(r); err != nil {
+			return
+		}
+
+		if tw.size() >= uint64(db.cfg.tableFileSize) {
+			if err = finish(); err != nil {
+				return
+			}
+		}
+	}
+
+	if err = it.err(); err != nil {
+		return
+	}
+
+	if tw != nil {
+		err = finish()
+	}
+
+	return
+}
@@ -0,0 +1,548 @@
+// Package kv 基于 LSM 树的嵌入式键值存储
+//
+// 写入先追加到预写日志（WAL），再写入以 skipList.SkipList 实现的内存表；
+// 内存表超过 WithMemtableSize 后写出为第 0 层的 SSTable，并切换到新的日志。
+// SSTable 是不可变的有序文件，带有稀疏索引和布隆过滤器。
+// 第 0 层的 table 之间 key 可能重叠，其他层按 key 划分互不重叠，每一层的容量是上一层的 10 倍，
+// 超过容量时与下一层合并（leveled compaction）。
+// MANIFEST 文件记录每一层的 table 和当前日志，重启时据此打开 table 并重放日志。
+//
+// flush 和合并在写入时同步执行。同一个目录同时只能被一个 DB 打开。
+package kv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrNotFound key 不存在
+	ErrNotFound = errors.New("kv: key 不存在")
+	// ErrEmptyKey key 不能为空
+	ErrEmptyKey = errors.New("kv: key 不能为空")
+	// ErrClosed DB 已关闭
+	ErrClosed = errors.New("kv: DB 已关闭")
+	// ErrCorrupted 数据文件已损坏
+	ErrCorrupted = errors.New("kv: 数据文件已损坏")
+	// ErrInvalidOption 配置超出有效范围
+	ErrInvalidOption = errors.New("kv: 配置无效")
+)
+
+const (
+	numLevels       = 7
+	levelMultiplier = 10
+)
+
+// Option DB 的可选配置
+type Option func(*config)
+
+type config struct {
+	memtableSize        int
+	blockSize           int
+	bloomBitsPerKey     int
+	l0CompactionTrigger int
+	tableFileSize       int64
+	levelSizeBase       int64
+	syncWrites          bool
+}
+
+func defaultConfig() config {
+	return config{
+		memtableSize:        4 << 20,
+		blockSize:           4 << 10,
+		bloomBitsPerKey:     10,
+		l0CompactionTrigger: 4,
+		tableFileSize:       2 << 20,
+		levelSizeBase:       10 << 20,
+	}
+}
+
+// validate 检查配置是否在有效范围内
+func (c *config) validate() error {
+	var reason string
+
+	switch {
+	case c.memtableSize <= 0:
+		reason = "内存表的大小必须大于 0"
+	case c.blockSize <= 0:
+		reason = "block 的大小必须大于 0"
+	case c.bloomBitsPerKey < 0:
+		reason = "布隆过滤器的位数不能小于 0"
+	case c.l0CompactionTrigger < 1:
+		reason = "第 0 层的合并阈值必须大于 0"
+	case c.tableFileSize <= 0:
+		reason = "SSTable 的大小必须大于 0"
+	case c.levelSizeBase <= 0:
+		reason = "第 1 层的容量必须大于 0"
+	default:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidOption, reason)
+}
+
+// WithMemtableSize 内存表超过 size 字节后写出为 SSTable，默认 4MB
+func WithMemtableSize(size int) Option {
+	return func(c *config) {
+		c.memtableSize = size
+	}
+}
+
+// WithBlockSize SSTable 中 data block 的大小，也是稀疏索引的间隔，默认 4KB
+func WithBlockSize(size int) Option {
+	return func(c *config) {
+		c.blockSize = size
+	}
+}
+
+// WithBloomBitsPerKey 布隆过滤器中每个 key 占用的位数，默认 10，误判率约 1%
+func WithBloomBitsPerKey(bits int) Option {
+	return func(c *config) {
+		c.bloomBitsPerKey = bits
+	}
+}
+
+// WithL0CompactionTrigger 第 0 层的 table 个数达到 n 时合并到第 1 层，默认 4
+func WithL0CompactionTrigger(n int) Option {
+	return func(c *config) {
+		c.l0CompactionTrigger = n
+	}
+}
+
+// WithTableFileSize 合并时输出的单个 SSTable 的大小，默认 2MB
+func WithTableFileSize(size int64) Option {
+	return func(c *config) {
+		c.tableFileSize = size
+	}
+}
+
+// WithLevelSizeBase 第 1 层的容量，之后每一层是上一层的 10 倍，默认 10MB
+func WithLevelSizeBase(size int64) Option {
+	return func(c *config) {
+		c.levelSizeBase = size
+	}
+}
+
+// WithSyncWrites 每次写入后等待日志落盘，默认关闭，进程崩溃不会丢失数据，但机器掉电可能丢失最近的写入
+func WithSyncWrites(sync bool) Option {
+	return func(c *config) {
+		c.syncWrites = sync
+	}
+}
+
+// DB 嵌入式键值存储，并发安全
+type DB struct {
+	mu     sync.RWMutex
+	dir    string
+	cfg    config
+	closed bool
+
+	mem      *memtable
+	log      *wal
+	logNum   uint64
+	nextFile uint64
+
+	// levels[0] 按写出的先后排列，其他层按 key 排列
+	levels         [numLevels][]*table
+	compactPointer [numLevels]string
+}
+
+// Open 打开 dir 中的 DB，目录不存在时创建
+// 打开时按 MANIFEST 打开 SSTable，重放日志恢复内存表，并删除崩溃时遗留的文件。
+// 配置超出有效范围时返回 ErrInvalidOption。
+func Open(dir string, opts ...Option) (db *DB, err error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err = cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	m, _, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	db = &DB{dir: dir, cfg: cfg, mem: newMemtable(), nextFile: max(m.NextFile, 1)}
+
+	defer func() {
+		if err != nil {
+			db.release()
+		}
+	}()
+
+	live := make(map[uint64]bool)
+
+	for level, nums := range m.Levels {
+		if level >= numLevels {
+			return nil, ErrCorrupted
+		}
+
+		for _, num := range nums {
+			t, err := openTable(fileName(dir, num, tableExt), num)
+			if err != nil {
+				return nil, err
+			}
+
+			db.levels[level] = append(db.levels[level], t)
+			live[num] = true
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []uint64
+
+	for _, entry := range entries {
+		num, ext, ok := parseFileName(entry.Name())
+		if !ok {
+			continue
+		}
+
+		db.nextFile = max(db.nextFile, num+1)
+
+		switch {
+		case ext == logExt && num >= m.Log:
+			logs = append(logs, num)
+		case ext == tableExt && live[num]:
+		default:
+			// 已经写入 SSTable 的日志，以及 flush 或合并中途崩溃留下的 SSTable
+			os.Remove(fileName(dir, num, ext))
+		}
+	}
+
+	slices.Sort(logs)
+
+	for _, num := range logs {
+		err = replayWAL(fileName(dir, num, logExt), func(kind byte, key string, value []byte) {
+			db.mem.put(key, value, kind == kindDelete)
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 恢复的内存表立即写出，之后旧的日志都可以删除
+	if err = db.rotateLocked(); err != nil {
+		return nil, err
+	}
+
+	for _, num := range logs {
+		os.Remove(fileName(dir, num, logExt))
+	}
+
+	if err = db.compactLocked(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (db *DB) newFileNum() uint64 {
+	num := db.nextFile
+	db.nextFile++
+	return num
+}
+
+// saveManifest 把即将切换到的 levels 和日志写入 MANIFEST
+// 调用方在写入成功之后才修改内存中的状态，失败时内存与 MANIFEST 保持一致。
+func (db *DB) saveManifest(levels [numLevels][]*table, logNum uint64) error {
+	m := manifest{NextFile: db.nextFile, Log: logNum, Levels: make([][]uint64, numLevels)}
+
+	for level, tables := range levels {
+		m.Levels[level] = []uint64{}
+
+		for _, t := range tables {
+			m.Levels[level] = append(m.Levels[level], t.num)
+		}
+	}
+
+	return writeManifest(db.dir, m)
+}
+
+// rotateLocked 把内存表写出为第 0 层的 SSTable，并切换到新的日志
+// 新的 MANIFEST 写入之后旧的日志才会被删除，中途崩溃时重放旧日志即可。
+func (db *DB) rotateLocked() error {
+	var outputs []*table
+
+	if db.mem.len() > 0 {
+		snapshot := db.mem.snapshot("", nil)
+		var err error
+
+		if outputs, err = db.writeTables(snapshot, false); err != nil {
+			return err
+		}
+	}
+
+	logNum := db.newFileNum()
+	log, err := createWAL(fileName(db.dir, logNum, logExt), db.cfg.syncWrites)
+	if err != nil {
+		for _, t := range outputs {
+			t.obsolete.Store(true)
+			t.unref()
+		}
+
+		return err
+	}
+
+	levels := db.levels
+	levels[0] = append(slices.Clone(levels[0]), outputs...)
+
+	if err = db.saveManifest(levels, logNum); err != nil {
+		log.close()
+		os.Remove(fileName(db.dir, logNum, logExt))
+
+		for _, t := range outputs {
+			t.obsolete.Store(true)
+			t.unref()
+		}
+
+		return err
+	}
+
+	oldLog, oldNum := db.log, db.logNum
+	db.log, db.logNum = log, logNum
+	db.levels = levels
+	db.mem = newMemtable()
+
+	if oldLog != nil {
+		oldLog.close()
+		os.Remove(fileName(db.dir, oldNum, logExt))
+	}
+
+	return nil
+}
+
+// Put 写入 key 对应的 value
+func (db *DB) Put(key, value []byte) error {
+	return db.write(kindPut, key, value)
+}
+
+// Delete 删除 key，key 不存在时不返回错误
+func (db *DB) Delete(key []byte) error {
+	return db.write(kindDelete, key, nil)
+}
+
+// write 先追加日志再写入内存表，之后 flush 或合并失败时仍返回错误，
+// 但记录已经生效，内存表和日志保持不变，下一次写入会重试 flush。
+func (db *DB) write(kind byte, key, value []byte) error {
+	if len(key) == 0 {
+		return ErrEmptyKey
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return ErrClosed
+	}
+
+	k := string(key)
+
+	if err := db.log.append(kind, k, value); err != nil {
+		return err
+	}
+
+	db.mem.put(k, bytes.Clone(value), kind == kindDelete)
+
+	if db.mem.size < db.cfg.memtableSize {
+		return nil
+	}
+
+	if err := db.rotateLocked(); err != nil {
+		return err
+	}
+
+	return db.compactLocked()
+}
+
+// Get 返回 key 对应的 value，不存在时返回 ErrNotFound
+// 依次查找内存表、第 0 层从新到旧的 table，以及其他层中 key 范围包含 key 的 table。
+func (db *DB) Get(key []byte) ([]byte, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	if db.closed {
+		return nil, ErrClosed
+	}
+
+	k := string(key)
+
+	if e, ok := db.mem.get(k); ok {
+		if e.deleted {
+			return nil, ErrNotFound
+		}
+
+		return bytes.Clone(e.value), nil
+	}
+
+	found := func(r record) ([]byte, error) {
+		if r.deleted {
+			return nil, ErrNotFound
+		}
+
+		return r.value, nil
+	}
+
+	for i := len(db.levels[0]) - 1; i >= 0; i-- {
+		r, ok, err := db.levels[0][i].get(k)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return found(r)
+		}
+	}
+
+	for level := 1; level < numLevels; level++ {
+		tables := db.levels[level]
+		i := sort.Search(len(tables), func(i int) bool {
+			return tables[i].largest >= k
+		})
+
+		if i == len(tables) {
+			continue
+		}
+
+		r, ok, err := tables[i].get(k)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			return found(r)
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// Scan 按 key 从小到大遍历 [start, end) 内的键值对，fn 返回 false 时停止
+// start 为 nil 时从第一个 key 开始，end 为 nil 时遍历到最后。
+// Scan 遍历的是调用时的快照，fn 中可以读写 DB，其修改对本次遍历不可见。
+func (db *DB) Scan(start, end []byte, fn func(key, value []byte) bool) error {
+	db.mu.RLock()
+
+	if db.closed {
+		db.mu.RUnlock()
+		return ErrClosed
+	}
+
+	from := string(start)
+	var to *string
+
+	if end != nil {
+		s := string(end)
+		to = &s
+	}
+
+	its := []iterator{db.mem.snapshot(from, to)}
+	var tables []*table
+
+	for i := len(db.levels[0]) - 1; i >= 0; i-- {
+		tables = append(tables, db.levels[0][i])
+	}
+
+	for level := 1; level < numLevels; level++ {
+		for _, t := range db.levels[level] {
+			if t.largest >= from && (to == nil || t.smallest < *to) {
+				tables = append(tables, t)
+			}
+		}
+	}
+
+	for _, t := range tables {
+		t.ref()
+		its = append(its, t.iter(from))
+	}
+
+	db.mu.RUnlock()
+
+	defer func() {
+		for _, t := range tables {
+			t.unref()
+		}
+	}()
+
+	it := newMergeIter(its)
+
+	for it.next() {
+		r := it.record()
+
+		if to != nil && r.key >= *to {
+			break
+		}
+
+		if r.deleted {
+			continue
+		}
+
+		if !fn([]byte(r.key), bytes.Clone(r.value)) {
+			break
+		}
+	}
+
+	return it.err()
+}
+
+// Flush 把内存表写出为 SSTable 并按需合并
+func (db *DB) Flush() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return ErrClosed
+	}
+
+	if err := db.rotateLocked(); err != nil {
+		return err
+	}
+
+	return db.compactLocked()
+}
+
+// Close 关闭 DB，内存表中的数据保存在日志中，下次打开时恢复
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if db.closed {
+		return ErrClosed
+	}
+
+	db.closed = true
+	return db.release()
+}
+
+// release 关闭日志并释放所有 table 的引用
+func (db *DB) release() (err error) {
+	if db.log != nil {
+		err = db.log.close()
+	}
+
+	for level := range db.levels {
+		for _, t := range db.levels[level] {
+			t.unref()
+		}
+
+		db.levels[level] = nil
+	}
+
+	return
+}
@@ -0,0 +1,360 @@
+package kv
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// smallOptions 使用很小的内存表和文件，少量数据就能触发 flush 和多层合并
+func smallOptions() []Option {
+	return []Option{
+		WithMemtableSize(4 << 10),
+		WithBlockSize(512),
+		WithTableFileSize(8 << 10),
+		WithLevelSizeBase(32 << 10),
+		WithL0CompactionTrigger(2),
+	}
+}
+
+func collect(t *testing.T, db *DB, start, end []byte) map[string]string {
+	t.Helper()
+	result := map[string]string{}
+	var prev string
+
+	err := db.Scan(start, end, func(key, value []byte) bool {
+		if len(result) > 0 && string(key) <= prev {
+			t.Fatalf("Scan 乱序: %q 在 %q 之后", key, prev)
+		}
+		prev = string(key)
+		result[string(key)] = string(value)
+		return true
+	})
+
+	assert.NoError(t, err)
+	return result
+}
+
+// checkLevels 校验第 1 层以后的 table 按 key 排列且互不重叠
+func checkLevels(t *testing.T, db *DB) {
+	t.Helper()
+
+	for level := 1; level < numLevels; level++ {
+		tables := db.levels[level]
+		for i := 1; i < len(tables); i++ {
+			if tables[i-1].largest >= tables[i].smallest {
+				t.Fatalf("第 %d 层的 table 重叠", level)
+			}
+		}
+	}
+}
+
+func TestDB_Basic(t *testing.T) {
+	a := assert.New(t)
+	db, err := Open(t.TempDir())
+	a.NoError(err)
+
+	a.NoError(db.Put([]byte("a"), []byte("1")))
+	a.NoError(db.Put([]byte("b"), []byte("2")))
+	a.NoError(db.Put([]byte("a"), []byte("3")))
+	a.ErrorIs(db.Put(nil, []byte("x")), ErrEmptyKey)
+
+	value, err := db.Get([]byte("a"))
+	a.NoError(err)
+	a.Equal("3", string(value))
+
+	a.NoError(db.Delete([]byte("b")))
+	_, err = db.Get([]byte("b"))
+	a.ErrorIs(err, ErrNotFound)
+
+	a.NoError(db.Flush())
+	value, err = db.Get([]byte("a"))
+	a.NoError(err)
+	a.Equal("3", string(value))
+	_, err = db.Get([]byte("b"))
+	a.ErrorIs(err, ErrNotFound)
+
+	a.Equal(map[string]string{"a": "3"}, collect(t, db, nil, nil))
+
+	a.NoError(db.Close())
+	_, err = db.Get([]byte("a"))
+	a.ErrorIs(err, ErrClosed)
+	a.ErrorIs(db.Put([]byte("a"), nil), ErrClosed)
+}
+
+func TestDB_InvalidOptions(t *testing.T) {
+	for _, opt := range []Option{
+		WithMemtableSize(0),
+		WithBlockSize(-1),
+		WithBloomBitsPerKey(-1),
+		WithL0CompactionTrigger(0),
+		WithTableFileSize(0),
+		WithLevelSizeBase(-1),
+	} {
+		_, err := Open(t.TempDir(), opt)
+		assert.ErrorIs(t, err, ErrInvalidOption)
+	}
+
+	// 即使配置被绕过，合并也不会因为没有输入而死循环
+	db, err := Open(t.TempDir())
+	assert.NoError(t, err)
+	db.cfg.l0CompactionTrigger = 0
+	assert.NoError(t, db.Put([]byte("k"), []byte("v")))
+	assert.NoError(t, db.Flush())
+	assert.NoError(t, db.Close())
+}
+
+func TestDB_Recovery(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+
+	db, err := Open(dir, smallOptions()...)
+	a.NoError(err)
+	a.NoError(db.Put([]byte("flushed"), []byte("1")))
+	a.NoError(db.Flush())
+	a.NoError(db.Put([]byte("logged"), []byte("2")))
+	a.NoError(db.Delete([]byte("flushed")))
+	a.NoError(db.Close())
+
+	// 模拟写入日志时崩溃：末尾是一条不完整的记录
+	logs, _ := filepath.Glob(filepath.Join(dir, "*"+logExt))
+	a.Len(logs, 1)
+	f, err := os.OpenFile(logs[0], os.O_APPEND|os.O_WRONLY, 0)
+	a.NoError(err)
+	f.Write([]byte{1, 2, 3, 4, 0, 0, 0, 100, 1})
+	f.Close()
+
+	// 模拟合并时崩溃：遗留的 SSTable 不在 MANIFEST 中
+	a.NoError(os.WriteFile(filepath.Join(dir, "000999.sst"), []byte("garbage"), 0o644))
+
+	db, err = Open(dir, smallOptions()...)
+	a.NoError(err)
+
+	value, err := db.Get([]byte("logged"))
+	a.NoError(err)
+	a.Equal("2", string(value))
+	_, err = db.Get([]byte("flushed"))
+	a.ErrorIs(err, ErrNotFound)
+
+	_, err = os.Stat(filepath.Join(dir, "000999.sst"))
+	a.True(os.IsNotExist(err))
+
+	// 遗留文件的编号不会被重用
+	a.NoError(db.Put([]byte("after"), []byte("3")))
+	a.NoError(db.Flush())
+	a.Greater(db.nextFile, uint64(999))
+	a.NoError(db.Close())
+}
+
+func TestDB_Random(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	r := rand.New(rand.NewSource(1))
+	model := map[string]string{}
+
+	db, err := Open(dir, smallOptions()...)
+	a.NoError(err)
+
+	for i := 0; i < 20000; i++ {
+		key := fmt.Sprintf("key%04d", r.Intn(2000))
+
+		switch r.Intn(10) {
+		case 0, 1, 2:
+			a.NoError(db.Delete([]byte(key)))
+			delete(model, key)
+		default:
+			value := strings.Repeat(fmt.Sprint(i), 1+r.Intn(8))
+			a.NoError(db.Put([]byte(key), []byte(value)))
+			model[key] = value
+		}
+
+		if i%5000 == 4999 {
+			a.NoError(db.Close())
+			db, err = Open(dir, smallOptions()...)
+			a.NoError(err)
+		}
+	}
+
+	checkLevels(t, db)
+	deepest := 0
+	for level := range db.levels {
+		if len(db.levels[level]) > 0 {
+			deepest = level
+		}
+	}
+	a.GreaterOrEqual(deepest, 2, "应当触发多层合并")
+
+	for i := 0; i < 2000; i++ {
+		key := fmt.Sprintf("key%04d", i)
+		value, err := db.Get([]byte(key))
+
+		if want, ok := model[key]; ok {
+			a.NoError(err)
+			a.Equal(want, string(value))
+		} else {
+			a.ErrorIs(err, ErrNotFound)
+		}
+	}
+
+	a.Equal(model, collect(t, db, nil, nil))
+
+	// 区间扫描
+	want := map[string]string{}
+	for _, key := range slices.Sorted(maps.Keys(model)) {
+		if key >= "key0500" && key < "key0600" {
+			want[key] = model[key]
+		}
+	}
+	a.Equal(want, collect(t, db, []byte("key0500"), []byte("key0600")))
+
+	a.NoError(db.Close())
+}
+
+// buildTable 把 keys 写成一个 SSTable
+func buildTable(t *testing.T, db *DB, keys ...string) *table {
+	t.Helper()
+	mem := newMemtable()
+	for _, key := range keys {
+		mem.put(key, []byte(key), false)
+	}
+
+	tables, err := db.writeTables(mem.snapshot("", nil), false)
+	assert.NoError(t, err)
+	assert.Len(t, tables, 1)
+	return tables[0]
+}
+
+func TestDB_CompactPointer(t *testing.T) {
+	a := assert.New(t)
+	db, err := Open(t.TempDir(), WithLevelSizeBase(1))
+	a.NoError(err)
+	defer db.Close()
+
+	// 第 2 层的 table 比输入更宽，合并位置不能越过第 1 层的 c、d
+	first, second, third := buildTable(t, db, "a", "b"), buildTable(t, db, "c", "d"), buildTable(t, db, "g", "h")
+	db.levels[1] = []*table{first, second, third}
+	db.levels[2] = []*table{buildTable(t, db, "b", "e")}
+
+	a.NoError(db.compact(1, []*table{first}))
+	a.Equal("b", db.compactPointer[1])
+
+	level, inputs := db.pickCompaction()
+	a.Equal(1, level)
+	a.Equal([]*table{second}, inputs)
+}
+
+func TestDB_ManifestFailure(t *testing.T) {
+	a := assert.New(t)
+	dir := t.TempDir()
+	db, err := Open(dir)
+	a.NoError(err)
+
+	a.NoError(db.Put([]byte("a"), []byte("1")))
+	a.NoError(db.Flush())
+	a.NoError(db.Put([]byte("b"), []byte("2")))
+
+	// 临时文件的位置被目录占用，写入 MANIFEST 失败
+	tmp := filepath.Join(dir, manifestName+".tmp")
+	a.NoError(os.Mkdir(tmp, 0o755))
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	levels, logNum := db.levels, db.logNum
+
+	a.Error(db.Flush())
+	a.Equal(levels, db.levels)
+	a.Equal(logNum, db.logNum)
+	a.Equal(1, db.mem.len())
+
+	a.Error(db.compact(0, slices.Clone(db.levels[0])))
+	a.Equal(levels, db.levels)
+
+	// 失败时新建的日志和 SSTable 都被删除
+	after, _ := filepath.Glob(filepath.Join(dir, "*"))
+	a.Equal(files, after)
+
+	a.NoError(os.Remove(tmp))
+	a.NoError(db.Put([]byte("c"), []byte("3")))
+	a.NoError(db.Close())
+
+	db, err = Open(dir)
+	a.NoError(err)
+	a.Equal(map[string]string{"a": "1", "b": "2", "c": "3"}, collect(t, db, nil, nil))
+	a.NoError(db.Close())
+}
+
+func TestDB_ScanSnapshot(t *testing.T) {
+	a := assert.New(t)
+	db, err := Open(t.TempDir(), smallOptions()...)
+	a.NoError(err)
+	defer db.Close()
+
+	for i := 0; i < 500; i++ {
+		a.NoError(db.Put([]byte(fmt.Sprintf("key%04d", i)), []byte("old")))
+	}
+
+	// 遍历时写入会触发 flush 和合并，遍历仍然看到调用 Scan 时的数据
+	count := 0
+	err = db.Scan(nil, nil, func(key, value []byte) bool {
+		a.Equal("old", string(value))
+		a.NoError(db.Put(key, []byte(strings.Repeat("new", 20))))
+		a.NoError(db.Delete([]byte(fmt.Sprintf("key%04d", 499-count))))
+		count++
+		return true
+	})
+	a.NoError(err)
+	a.Equal(500, count)
+
+	// 合并掉的 table 在遍历结束后才删除
+	files, _ := filepath.Glob(filepath.Join(db.dir, "*"+tableExt))
+	live := 0
+	for _, tables := range db.levels {
+		live += len(tables)
+	}
+	a.Equal(live, len(files))
+}
+
+func TestDB_Concurrent(t *testing.T) {
+	db, err := Open(t.TempDir(), smallOptions()...)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := []byte(fmt.Sprintf("w%d-%04d", w, i))
+				if err := db.Put(key, key); err != nil {
+					t.Error(err)
+					return
+				}
+				if value, err := db.Get(key); err != nil || string(value) != string(key) {
+					t.Errorf("Get(%s) = %s, %v", key, value, err)
+					return
+				}
+			}
+		}(w)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if err := db.Scan(nil, nil, func(key, value []byte) bool { return true }); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	assert.Len(t, collect(t, db, nil, nil), 4000)
+}
@@ -0,0 +1,104 @@
+package kv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// manifest 记录 DB 的持久化状态：每一层的 SSTable 以及当前日志的编号
+// 每次 flush 或合并后先写入临时文件再重命名，因此崩溃时只会看到旧的或新的 manifest。
+type manifest struct {
+	NextFile uint64     `json:"next_file"`
+	Log      uint64     `json:"log"`
+	Levels   [][]uint64 `json:"levels"`
+}
+
+const (
+	manifestName = "MANIFEST"
+	tableExt     = ".sst"
+	logExt       = ".log"
+)
+
+func fileName(dir string, num uint64, ext string) string {
+	return filepath.Join(dir, fmt.Sprintf("%06d%s", num, ext))
+}
+
+// parseFileName 解析 SSTable 和日志的文件名，返回编号和扩展名
+func parseFileName(name string) (num uint64, ext string, ok bool) {
+	ext = filepath.Ext(name)
+
+	if ext != tableExt && ext != logExt {
+		return 0, "", false
+	}
+
+	num, err := strconv.ParseUint(strings.TrimSuffix(name, ext), 10, 64)
+	return num, ext, err == nil
+}
+
+// readManifest 读取 manifest，不存在时返回 false
+func readManifest(dir string) (m manifest, ok bool, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, false, nil
+	}
+
+	if err != nil {
+		return m, false, err
+	}
+
+	if err = json.Unmarshal(data, &m); err != nil {
+		return m, false, ErrCorrupted
+	}
+
+	return m, true, nil
+}
+
+// writeManifest 原子地替换 manifest
+func writeManifest(dir string, m manifest) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(dir, manifestName+".tmp")
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, filepath.Join(dir, manifestName)); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir 同步目录，使文件的创建和重命名落盘，不支持时忽略
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	d.Sync()
+	return d.Close()
+}
@@ -0,0 +1,53 @@
+package kv
+
+import (
+	"github.com/BeginerAndProgresses/generalized-tools/skipList"
+)
+
+// memEntry 内存表中的值，deleted 为 true 时表示删除标记
+type memEntry struct {
+	value   []byte
+	deleted bool
+}
+
+// entryOverhead 估算内存占用时每个元素额外的字节数
+const entryOverhead = 32
+
+// memtable 内存表，以 SkipList 按 key 排序，所有修改先写入内存表
+type memtable struct {
+	list *skipList.SkipList[string, memEntry]
+	size int
+}
+
+func newMemtable() *memtable {
+	return &memtable{list: skipList.New[string, memEntry]()}
+}
+
+// put 写入值或删除标记，size 只增加不减少，是内存占用的上界
+func (m *memtable) put(key string, value []byte, deleted bool) {
+	m.list.Set(key, memEntry{value: value, deleted: deleted})
+	m.size += len(key) + len(value) + entryOverhead
+}
+
+func (m *memtable) get(key string) (memEntry, bool) {
+	return m.list.GetValue(key)
+}
+
+func (m *memtable) len() int {
+	return m.list.Len()
+}
+
+// snapshot 复制 [start, end) 内的元素，end 为 nil 时没有上界
+func (m *memtable) snapshot(start string, end *string) *sliceIter {
+	it := &sliceIter{pos: -1}
+
+	for key, e := range m.list.Ascend(start) {
+		if end != nil && key >= *end {
+			break
+		}
+
+		it.records = append(it.records, record{key: key, value: e.value, deleted: e.deleted})
+	}
+
+	return it
+}
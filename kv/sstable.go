@@ -0,0 +1,521 @@
+package kv
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"sort"
+	"sync/atomic"
+)
+
+// SSTable 不可变的有序文件，格式：
+//
+//	data block... | index block | bloom block | meta block | footer
+//
+// 每个 block 后面跟着 4 字节的 crc32。
+//   - data block：若干条 kind（1 字节）| key 长度（uvarint）| value 长度（uvarint）| key | value
+//   - index block：稀疏索引，每个 data block 一条：第一个 key 的长度（uvarint）| key | offset（uvarint）| 长度（uvarint）
+//   - bloom block：所有 key 的布隆过滤器
+//   - meta block：元素个数（uvarint）| 最小 key | 最大 key，key 之前是长度（uvarint）
+//   - footer：index、bloom、meta 的 offset 和长度（各 8 字节大端序）加上 8 字节的 magic
+
+const (
+	kindDelete byte = iota
+	kindPut
+)
+
+const (
+	tableMagic       = 0x6774737374626c31 // "gtsstbl1"
+	footerSize       = 7 * 8
+	blockTrailerSize = 4
+)
+
+// blockHandle block 在文件中的位置，length 不包含 crc32
+type blockHandle struct {
+	offset uint64
+	length uint64
+}
+
+type indexEntry struct {
+	firstKey string
+	handle   blockHandle
+}
+
+// record 一条键值对或删除标记
+type record struct {
+	key     string
+	value   []byte
+	deleted bool
+}
+
+// iterator 按 key 从小到大遍历 record
+type iterator interface {
+	next() bool
+	record() record
+	err() error
+}
+
+// sliceIter 遍历内存中的 record
+type sliceIter struct {
+	records []record
+	pos     int
+}
+
+func (it *sliceIter) next() bool {
+	it.pos++
+	return it.pos < len(it.records)
+}
+
+func (it *sliceIter) record() record {
+	return it.records[it.pos]
+}
+
+func (it *sliceIter) err() error {
+	return nil
+}
+
+// tableWriter 按 key 从小到大写入 SSTable
+type tableWriter struct {
+	f          *os.File
+	w          *bufio.Writer
+	offset     uint64
+	blockSize  int
+	bitsPerKey int
+
+	block      []byte
+	blockFirst string
+	index      []indexEntry
+	hashes     []uint64
+	count      int
+	smallest   string
+	largest    string
+}
+
+func newTableWriter(path string, blockSize, bitsPerKey int) (*tableWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tableWriter{
+		f:          f,
+		w:          bufio.NewWriter(f),
+		blockSize:  blockSize,
+		bitsPerKey: bitsPerKey,
+	}, nil
+}
+
+// size 已写入的字节数加上当前 block 的大小
+func (tw *tableWriter) size() uint64 {
+	return tw.offset + uint64(len(tw.block))
+}
+
+// add 追加一条 record，key 必须大于之前的所有 key
+func (tw *tableWriter) add(r record) error {
+	if tw.count == 0 {
+		tw.smallest = r.key
+	}
+
+	if len(tw.block) == 0 {
+		tw.blockFirst = r.key
+	}
+
+	kind := kindPut
+	if r.deleted {
+		kind = kindDelete
+	}
+
+	tw.block = append(tw.block, kind)
+	tw.block = binary.AppendUvarint(tw.block, uint64(len(r.key)))
+	tw.block = binary.AppendUvarint(tw.block, uint64(len(r.value)))
+	tw.block = append(tw.block, r.key...)
+	tw.block = append(tw.block, r.value...)
+	tw.hashes = append(tw.hashes, bloomHash(r.key))
+	tw.largest = r.key
+	tw.count++
+
+	if len(tw.block) >= tw.blockSize {
+		return tw.flushBlock()
+	}
+
+	return nil
+}
+
+// writeBlock 写入 data 及其 crc32，返回 block 的位置
+func (tw *tableWriter) writeBlock(data []byte) (blockHandle, error) {
+	handle := blockHandle{offset: tw.offset, length: uint64(len(data))}
+
+	if _, err := tw.w.Write(data); err != nil {
+		return handle, err
+	}
+
+	var sum [blockTrailerSize]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(data))
+
+	if _, err := tw.w.Write(sum[:]); err != nil {
+		return handle, err
+	}
+
+	tw.offset += uint64(len(data)) + blockTrailerSize
+	return handle, nil
+}
+
+func (tw *tableWriter) flushBlock() error {
+	if len(tw.block) == 0 {
+		return nil
+	}
+
+	handle, err := tw.writeBlock(tw.block)
+	if err != nil {
+		return err
+	}
+
+	tw.index = append(tw.index, indexEntry{firstKey: tw.blockFirst, handle: handle})
+	tw.block = tw.block[:0]
+	return nil
+}
+
+// finish 写入索引、布隆过滤器、元数据和 footer，并同步到磁盘
+func (tw *tableWriter) finish() (err error) {
+	defer func() {
+		if cerr := tw.f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if err = tw.flushBlock(); err != nil {
+		return err
+	}
+
+	var index []byte
+	for _, e := range tw.index {
+		index = binary.AppendUvarint(index, uint64(len(e.firstKey)))
+		index = append(index, e.firstKey...)
+		index = binary.AppendUvarint(index, e.handle.offset)
+		index = binary.AppendUvarint(index, e.handle.length)
+	}
+
+	var meta []byte
+	meta = binary.AppendUvarint(meta, uint64(tw.count))
+	meta = binary.AppendUvarint(meta, uint64(len(tw.smallest)))
+	meta = append(meta, tw.smallest...)
+	meta = binary.AppendUvarint(meta, uint64(len(tw.largest)))
+	meta = append(meta, tw.largest...)
+
+	var footer []byte
+	for _, data := range [][]byte{index, newBloom(tw.hashes, tw.bitsPerKey), meta} {
+		handle, err := tw.writeBlock(data)
+		if err != nil {
+			return err
+		}
+
+		footer = binary.BigEndian.AppendUint64(footer, handle.offset)
+		footer = binary.BigEndian.AppendUint64(footer, handle.length)
+	}
+
+	footer = binary.BigEndian.AppendUint64(footer, tableMagic)
+
+	if _, err = tw.w.Write(footer); err != nil {
+		return err
+	}
+
+	if err = tw.w.Flush(); err != nil {
+		return err
+	}
+
+	return tw.f.Sync()
+}
+
+// abort 放弃写入并删除文件
+func (tw *tableWriter) abort() {
+	tw.f.Close()
+	os.Remove(tw.f.Name())
+}
+
+// table 打开的 SSTable，索引和布隆过滤器常驻内存
+// refs 为引用计数，DB 持有一个引用，Scan 期间额外持有一个引用；
+// 被合并掉的 table 标记为 obsolete，引用计数为 0 时关闭并删除文件。
+type table struct {
+	num      uint64
+	f        *os.File
+	size     int64
+	index    []indexEntry
+	filter   bloom
+	count    int
+	smallest string
+	largest  string
+
+	refs     atomic.Int32
+	obsolete atomic.Bool
+}
+
+func openTable(path string, num uint64) (t *table, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			f.Close()
+		}
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() < footerSize {
+		return nil, ErrCorrupted
+	}
+
+	var footer [footerSize]byte
+	if _, err = f.ReadAt(footer[:], info.Size()-footerSize); err != nil {
+		return nil, err
+	}
+
+	if binary.BigEndian.Uint64(footer[footerSize-8:]) != tableMagic {
+		return nil, ErrCorrupted
+	}
+
+	t = &table{num: num, f: f, size: info.Size()}
+	var blocks [3][]byte
+
+	for i := range blocks {
+		handle := blockHandle{
+			offset: binary.BigEndian.Uint64(footer[i*16:]),
+			length: binary.BigEndian.Uint64(footer[i*16+8:]),
+		}
+
+		if blocks[i], err = t.readBlock(handle); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.index, err = decodeIndex(blocks[0]); err != nil {
+		return nil, err
+	}
+
+	t.filter = blocks[1]
+
+	if err = t.decodeMeta(blocks[2]); err != nil {
+		return nil, err
+	}
+
+	t.refs.Store(1)
+	return t, nil
+}
+
+// uvarintBytes 读取长度前缀的字节串，返回剩余的数据
+func uvarintBytes(data []byte) ([]byte, []byte, bool) {
+	size, n := binary.Uvarint(data)
+
+	if n <= 0 || size > uint64(len(data)-n) {
+		return nil, nil, false
+	}
+
+	return data[n : n+int(size)], data[n+int(size):], true
+}
+
+func decodeIndex(data []byte) ([]indexEntry, error) {
+	var index []indexEntry
+
+	for len(data) > 0 {
+		key, rest, ok := uvarintBytes(data)
+		if !ok {
+			return nil, ErrCorrupted
+		}
+
+		offset, n1 := binary.Uvarint(rest)
+		if n1 <= 0 {
+			return nil, ErrCorrupted
+		}
+
+		length, n2 := binary.Uvarint(rest[n1:])
+		if n2 <= 0 {
+			return nil, ErrCorrupted
+		}
+
+		index = append(index, indexEntry{firstKey: string(key), handle: blockHandle{offset: offset, length: length}})
+		data = rest[n1+n2:]
+	}
+
+	return index, nil
+}
+
+func (t *table) decodeMeta(data []byte) error {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return ErrCorrupted
+	}
+
+	smallest, rest, ok := uvarintBytes(data[n:])
+	if !ok {
+		return ErrCorrupted
+	}
+
+	largest, _, ok := uvarintBytes(rest)
+	if !ok {
+		return ErrCorrupted
+	}
+
+	t.count = int(count)
+	t.smallest = string(smallest)
+	t.largest = string(largest)
+	return nil
+}
+
+// readBlock 读取 block 并校验 crc32
+func (t *table) readBlock(handle blockHandle) ([]byte, error) {
+	if handle.offset > uint64(t.size) || handle.length+blockTrailerSize > uint64(t.size)-handle.offset {
+		return nil, ErrCorrupted
+	}
+
+	buf := make([]byte, handle.length+blockTrailerSize)
+	if _, err := t.f.ReadAt(buf, int64(handle.offset)); err != nil {
+		return nil, err
+	}
+
+	data := buf[:handle.length]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(buf[handle.length:]) {
+		return nil, ErrCorrupted
+	}
+
+	return data, nil
+}
+
+// blockFor 返回可能包含 key 的 block 的下标，即第一个 key 不大于 key 的最后一个 block
+func (t *table) blockFor(key string) int {
+	return max(sort.Search(len(t.index), func(i int) bool {
+		return t.index[i].firstKey > key
+	})-1, 0)
+}
+
+// decodeRecord 解析 block 中的一条 record，返回剩余的数据
+func decodeRecord(data []byte) (record, []byte, error) {
+	if len(data) < 1 {
+		return record{}, nil, ErrCorrupted
+	}
+
+	kind := data[0]
+	keyLen, n1 := binary.Uvarint(data[1:])
+	if n1 <= 0 {
+		return record{}, nil, ErrCorrupted
+	}
+
+	valueLen, n2 := binary.Uvarint(data[1+n1:])
+	if n2 <= 0 {
+		return record{}, nil, ErrCorrupted
+	}
+
+	data = data[1+n1+n2:]
+	if keyLen+valueLen > uint64(len(data)) || kind > kindPut {
+		return record{}, nil, ErrCorrupted
+	}
+
+	r := record{
+		key:     string(data[:keyLen]),
+		value:   data[keyLen : keyLen+valueLen : keyLen+valueLen],
+		deleted: kind == kindDelete,
+	}
+
+	return r, data[keyLen+valueLen:], nil
+}
+
+// get 查找 key，found 为 false 时表示 table 中没有 key
+func (t *table) get(key string) (r record, found bool, err error) {
+	if key < t.smallest || key > t.largest || !t.filter.mayContain(key) {
+		return
+	}
+
+	data, err := t.readBlock(t.index[t.blockFor(key)].handle)
+	if err != nil {
+		return
+	}
+
+	for len(data) > 0 {
+		if r, data, err = decodeRecord(data); err != nil {
+			return
+		}
+
+		if r.key == key {
+			return r, true, nil
+		}
+
+		if r.key > key {
+			break
+		}
+	}
+
+	return record{}, false, nil
+}
+
+// overlaps 返回 table 的 key 范围是否与 [smallest, largest] 相交
+func (t *table) overlaps(smallest, largest string) bool {
+	return t.smallest <= largest && smallest <= t.largest
+}
+
+func (t *table) ref() {
+	t.refs.Add(1)
+}
+
+// unref 释放一个引用，引用计数为 0 时关闭文件，已被合并掉的 table 同时删除文件
+func (t *table) unref() {
+	if t.refs.Add(-1) == 0 {
+		t.f.Close()
+
+		if t.obsolete.Load() {
+			os.Remove(t.f.Name())
+		}
+	}
+}
+
+// tableIter 从第一个不小于 start 的 key 开始遍历 table
+type tableIter struct {
+	t     *table
+	start string
+	block int
+	data  []byte
+	curr  record
+	e     error
+}
+
+func (t *table) iter(start string) *tableIter {
+	return &tableIter{t: t, start: start, block: t.blockFor(start) - 1}
+}
+
+func (it *tableIter) next() bool {
+	for it.e == nil {
+		for len(it.data) == 0 {
+			it.block++
+
+			if it.block >= len(it.t.index) {
+				return false
+			}
+
+			if it.data, it.e = it.t.readBlock(it.t.index[it.block].handle); it.e != nil {
+				return false
+			}
+		}
+
+		if it.curr, it.data, it.e = decodeRecord(it.data); it.e != nil {
+			return false
+		}
+
+		if it.curr.key >= it.start {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (it *tableIter) record() record {
+	return it.curr
+}
+
+func (it *tableIter) err() error {
+	return it.e
+}
@@ -0,0 +1,111 @@
+package kv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestTable(t *testing.T, path string, n int) {
+	tw, err := newTableWriter(path, 256, 10)
+	assert.NoError(t, err)
+
+	for i := 0; i < n; i++ {
+		r := record{key: fmt.Sprintf("key%05d", i*2), value: []byte(fmt.Sprint("value", i))}
+		r.deleted = i%10 == 9
+		if r.deleted {
+			r.value = nil
+		}
+		assert.NoError(t, tw.add(r))
+	}
+
+	assert.NoError(t, tw.finish())
+}
+
+func TestTable(t *testing.T) {
+	a := assert.New(t)
+	path := filepath.Join(t.TempDir(), "000001.sst")
+	writeTestTable(t, path, 1000)
+
+	tbl, err := openTable(path, 1)
+	a.NoError(err)
+	defer tbl.unref()
+
+	a.Equal(1000, tbl.count)
+	a.Equal("key00000", tbl.smallest)
+	a.Equal("key01998", tbl.largest)
+	a.Greater(len(tbl.index), 10)
+
+	r, ok, err := tbl.get("key00100")
+	a.NoError(err)
+	a.True(ok)
+	a.Equal("value50", string(r.value))
+
+	r, ok, err = tbl.get("key00018")
+	a.NoError(err)
+	a.True(ok)
+	a.True(r.deleted)
+
+	for _, key := range []string{"key00101", "a", "z", "key01999"} {
+		_, ok, err = tbl.get(key)
+		a.NoError(err)
+		a.False(ok, key)
+	}
+
+	// 从中间开始遍历
+	it := tbl.iter("key01001")
+	var keys []string
+	for it.next() {
+		keys = append(keys, it.record().key)
+	}
+	a.NoError(it.err())
+	a.Equal(499, len(keys))
+	a.Equal("key01002", keys[0])
+	a.Equal("key01998", keys[len(keys)-1])
+}
+
+func TestTable_Corrupted(t *testing.T) {
+	a := assert.New(t)
+	path := filepath.Join(t.TempDir(), "000001.sst")
+	writeTestTable(t, path, 100)
+
+	data, err := os.ReadFile(path)
+	a.NoError(err)
+	data[10] ^= 0xff
+	a.NoError(os.WriteFile(path, data, 0o644))
+
+	tbl, err := openTable(path, 1)
+	a.NoError(err)
+	defer tbl.unref()
+
+	_, _, err = tbl.get("key00000")
+	a.ErrorIs(err, ErrCorrupted)
+
+	a.NoError(os.WriteFile(path, data[:len(data)-1], 0o644))
+	_, err = openTable(path, 1)
+	a.ErrorIs(err, ErrCorrupted)
+}
+
+func TestBloom(t *testing.T) {
+	a := assert.New(t)
+	var hashes []uint64
+	for i := 0; i < 10000; i++ {
+		hashes = append(hashes, bloomHash(fmt.Sprint("in", i)))
+	}
+	filter := newBloom(hashes, 10)
+
+	for i := 0; i < 10000; i++ {
+		a.True(filter.mayContain(fmt.Sprint("in", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if filter.mayContain(fmt.Sprint("out", i)) {
+			falsePositives++
+		}
+	}
+	a.Less(falsePositives, 300)
+}
@@ -0,0 +1,94 @@
+package kv
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+)
+
+// 预写日志（WAL）
+// 每条记录为 crc32（4 字节）| 长度（4 字节）| kind（1 字节）| key 长度（uvarint）| key | value，
+// crc32 覆盖长度之后的所有字节。写入内存表之前先写入日志，重启时重放日志恢复内存表。
+
+const walHeaderSize = 8
+
+type wal struct {
+	f    *os.File
+	sync bool
+	buf  []byte
+}
+
+func createWAL(path string, sync bool) (*wal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wal{f: f, sync: sync}, nil
+}
+
+// append 写入一条记录，sync 为 true 时等待数据落盘
+func (w *wal) append(kind byte, key string, value []byte) error {
+	buf := append(w.buf[:0], make([]byte, walHeaderSize)...)
+	buf = append(buf, kind)
+	buf = binary.AppendUvarint(buf, uint64(len(key)))
+	buf = append(buf, key...)
+	buf = append(buf, value...)
+
+	payload := buf[walHeaderSize:]
+	binary.BigEndian.PutUint32(buf[4:], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf, crc32.ChecksumIEEE(buf[4:]))
+	w.buf = buf
+
+	if _, err := w.f.Write(buf); err != nil {
+		return err
+	}
+
+	if w.sync {
+		return w.f.Sync()
+	}
+
+	return nil
+}
+
+func (w *wal) close() error {
+	return w.f.Close()
+}
+
+// replayWAL 按顺序读取日志中的记录
+// 末尾不完整或校验失败的记录是写入过程中崩溃留下的，从这里开始的内容都被忽略。
+func replayWAL(path string, fn func(kind byte, key string, value []byte)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for len(data) >= walHeaderSize {
+		size := binary.BigEndian.Uint32(data[4:])
+
+		if uint64(len(data)-walHeaderSize) < uint64(size) {
+			return nil
+		}
+
+		if crc32.ChecksumIEEE(data[4:walHeaderSize+size]) != binary.BigEndian.Uint32(data) {
+			return nil
+		}
+
+		payload := data[walHeaderSize : walHeaderSize+size]
+		data = data[walHeaderSize+size:]
+
+		if len(payload) < 1 {
+			return nil
+		}
+
+		keyLen, n := binary.Uvarint(payload[1:])
+		if n <= 0 || keyLen > uint64(len(payload)-1-n) {
+			return nil
+		}
+
+		key := payload[1+n : 1+n+int(keyLen)]
+		fn(payload[0], string(key), payload[1+n+int(keyLen):])
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package skipList
+
+// 元素按层数分为若干大小等级，每个等级用一个结构体把 Element 与 levels、spans 的底层数组放在一起，
+// 创建元素只需要一次内存分配。层数超过最大等级时单独分配数组。
+// 层数以 1/2 的概率递增时，约 3/4 的元素落在前两个等级中，浪费的空间很少。
+var elementSizeClasses = [...]int{1, 2, 4, 8, 16, 32, 48}
+
+type inlineElement1[K, V any] struct {
+	Element[K, V]
+	levelArray [1]*Element[K, V]
+	spanArray  [1]int
+}
+
+type inlineElement2[K, V any] struct {
+	Element[K, V]
+	levelArray [2]*Element[K, V]
+	spanArray  [2]int
+}
+
+type inlineElement4[K, V any] struct {
+	Element[K, V]
+	levelArray [4]*Element[K, V]
+	spanArray  [4]int
+}
+
+type inlineElement8[K, V any] struct {
+	Element[K, V]
+	levelArray [8]*Element[K, V]
+	spanArray  [8]int
+}
+
+type inlineElement16[K, V any] struct {
+	Element[K, V]
+	levelArray [16]*Element[K, V]
+	spanArray  [16]int
+}
+
+type inlineElement32[K, V any] struct {
+	Element[K, V]
+	levelArray [32]*Element[K, V]
+	spanArray  [32]int
+}
+
+type inlineElement48[K, V any] struct {
+	Element[K, V]
+	levelArray [48]*Element[K, V]
+	spanArray  [48]int
+}
+
+// sizeClass 返回能容纳 level 层的最小等级的下标，超过最大等级时返回 -1
+func sizeClass(level int) int {
+	for i, size := range elementSizeClasses {
+		if level <= size {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// allocElement 分配一个 level 层的元素，levels 和 spans 的容量为所在等级的大小
+func allocElement[K, V any](level int) *Element[K, V] {
+	var elem *Element[K, V]
+	var levels []*Element[K, V]
+	var spans []int
+
+	switch sizeClass(level) {
+	case 0:
+		e := new(inlineElement1[K, V])
+		elem, levels, spans = &e.Element, e.levelArray[:], e.spanArray[:]
+	case 1:
+		e := new(inlineElement2[K, V])
+		elem, levels, spans = &e.Element, e.levelArray[:], e.spanArray[:]
+	case 2:
+		e := new(inlineElement4[K, V])
+		elem, levels, spans = &e.Element, e.levelArray[:], e.spanArray[:]
+	case 3:
+		e := new(inlineElement8[K, V])
+		elem, levels, spans = &e.Element, e.levelArray[:], e.spanArray[:]
+	case 4:
+		e := new(inlineElement16[K, V])
+		elem, levels, spans = &e.Element, e.levelArray[:], e.spanArray[:]
+	case 5:
+		e := new(inlineElement32[K, V])
+		elem, levels, spans = &e.Element, e.levelArray[:], e.spanArray[:]
+	case 6:
+		e := new(inlineElement48[K, V])
+		elem, levels, spans = &e.Element, e.levelArray[:], e.spanArray[:]
+	default:
+		elem = new(Element[K, V])
+		levels = make([]*Element[K, V], level)
+		spans = make([]int, level)
+	}
+
+	elem.levels = levels[:level]
+	elem.spans = spans[:level]
+	return elem
+}
+
+// SetRecycle 设置是否回收被删除的元素，默认不回收。
+// 开启后被删除的元素按大小等级放入列表自己的空闲链表，之后插入元素时优先复用，
+// 在频繁插入和删除的场景下几乎不再分配内存。
+//
+// 注意：开启后 Remove、RemoveFront 等返回的元素的 key 和 value 已被清零，
+// 需要的话应当在删除之前读取；被删除的元素之后可能作为新的元素重新加入列表，
+// 调用方不能继续持有它。每个大小等级最多保留 maxFreeElements 个空闲元素，
+// 超出的交给 GC 回收。关闭时释放所有空闲的元素。
+func (list *SkipList[K, V]) SetRecycle(enabled bool) {
+	list.recycle = enabled
+
+	if !enabled {
+		list.free = [len(elementSizeClasses)][]*Element[K, V]{}
+	}
+}
+
+// maxFreeElements 每个大小等级最多保留的空闲元素个数
+const maxFreeElements = 256
+
+// release 处理从列表中删除的元素，开启回收时放入空闲链表
+// 放入空闲链表的元素清零 key、value 和所有指针，不再引用调用方的数据和列表中的其他元素。
+func (list *SkipList[K, V]) release(elem *Element[K, V]) {
+	class := sizeClass(cap(elem.levels))
+
+	if !list.recycle || class < 0 || elementSizeClasses[class] != cap(elem.levels) ||
+		len(list.free[class]) >= maxFreeElements {
+		elem.reset()
+		return
+	}
+
+	var zeroKey K
+	var zeroValue V
+	elem.key = zeroKey
+	elem.Value = zeroValue
+	elem.score = 0
+	elem.expireAt = 0
+	elem.list = nil
+	elem.prev = nil
+	elem.prevTopLevel = nil
+	clear(elem.levels[:cap(elem.levels)])
+	elem.levels = elem.levels[:0]
+	elem.spans = elem.spans[:0]
+	list.free[class] = append(list.free[class], elem)
+}
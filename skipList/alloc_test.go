@@ -0,0 +1,143 @@
+package skipList
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocElement(t *testing.T) {
+	a := assert.New(t)
+
+	for _, level := range []int{1, 2, 3, 5, 16, 17, 48, 49, 64} {
+		elem := allocElement[int, int](level)
+		a.Equal(level, elem.Level())
+		a.Len(elem.spans, level)
+
+		if class := sizeClass(level); class >= 0 {
+			a.Equal(elementSizeClasses[class], cap(elem.levels))
+		} else {
+			a.Equal(level, cap(elem.levels))
+		}
+	}
+}
+
+func TestRecycle(t *testing.T) {
+	a := assert.New(t)
+	list := NewWithOptions[int, int](Options{Seed: 1})
+	list.SetRecycle(true)
+	rnd := rand.New(rand.NewSource(1))
+	model := map[int]int{}
+
+	for i := 0; i < 5000; i++ {
+		key := rnd.Intn(300)
+
+		switch rnd.Intn(4) {
+		case 0:
+			if elem := list.Remove(key); elem != nil {
+				// 回收的元素不再引用 key、value 和其他元素
+				a.Zero(elem.Key())
+				a.Zero(elem.Value)
+				a.Nil(elem.Next())
+				a.Nil(elem.Prev())
+				list.RemoveElement(elem)
+			}
+			delete(model, key)
+		case 1:
+			if front := list.Front(); front != nil {
+				delete(model, front.Key())
+				list.RemoveFront()
+			}
+		default:
+			list.Set(key, i)
+			model[key] = i
+		}
+
+		if i%500 == 0 {
+			checkValid(t, list)
+		}
+	}
+
+	checkValid(t, list)
+	a.Equal(len(model), list.Len())
+	for key, value := range model {
+		a.Equal(value, list.Get(key).Value)
+	}
+
+	for i := 0; i < 5000; i++ {
+		list.Set(i, i)
+	}
+	list.RemoveRangeByRank(0, -1)
+	a.Equal(0, list.Len())
+	a.NotZero(len(list.free[0]))
+	for _, free := range list.free {
+		a.LessOrEqual(len(free), maxFreeElements)
+		for _, elem := range free {
+			a.Zero(elem.key)
+			a.Nil(elem.list)
+			for _, next := range elem.levels[:cap(elem.levels)] {
+				a.Nil(next)
+			}
+		}
+	}
+
+	list.SetRecycle(false)
+	a.Zero(len(list.free[0]))
+}
+
+func TestRecycle_Allocs(t *testing.T) {
+	list := NewWithOptions[int, int](Options{Seed: 1})
+	for i := 0; i < 1000; i++ {
+		list.Set(i, i)
+	}
+
+	// 元素与层数组在一次分配中创建
+	key := 1000
+	allocs := testing.AllocsPerRun(1000, func() {
+		list.Set(key, key)
+		key++
+	})
+	assert.LessOrEqual(t, allocs, 1.0)
+
+	list.SetRecycle(true)
+	for i := 0; i < 1000; i++ {
+		list.RemoveFront()
+	}
+
+	allocs = testing.AllocsPerRun(1000, func() {
+		list.RemoveFront()
+		list.Set(key, key)
+		key++
+	})
+	assert.Zero(t, allocs)
+	checkValid(t, list)
+}
+
+// BenchmarkChurn 删除再插入同一个 key，对比是否回收元素
+func BenchmarkChurn(b *testing.B) {
+	keys := benchKeys()
+
+	for _, recycle := range []bool{false, true} {
+		name := "Default"
+		if recycle {
+			name = "Recycle"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			list := New[int, int]()
+			list.SetRecycle(recycle)
+			for _, key := range keys {
+				list.Set(key, key)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				key := keys[i%benchSize]
+				list.Remove(key)
+				list.Set(key, i)
+			}
+		})
+	}
+}
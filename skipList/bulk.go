@@ -0,0 +1,224 @@
+package skipList
+
+import (
+	"errors"
+	"iter"
+	"math"
+)
+
+// ErrNotSorted FromSorted 的 key 没有按从小到大排列
+var ErrNotSorted = errors.New("skiplist: key 必须按从小到大排列")
+
+// builder 按从小到大的顺序在列表末尾追加元素，记录每一层最后的节点及其排名，
+// 因此每次追加只需要 O(level) 的时间。
+type builder[K, V any] struct {
+	list  *SkipList[K, V]
+	tails []*elementHeader[K, V]
+	ranks []int // tails 中各节点的排名，头节点为 0
+}
+
+// newBuilder 清空 list 并返回在其上追加元素的 builder
+func newBuilder[K, V any](list *SkipList[K, V]) *builder[K, V] {
+	list.Init()
+	max := len(list.levels)
+	b := &builder[K, V]{
+		list:  list,
+		tails: make([]*elementHeader[K, V], max),
+		ranks: make([]int, max),
+	}
+
+	for i := range b.tails {
+		b.tails[i] = &list.elementHeader
+	}
+
+	return b
+}
+
+// append 把 elem 追加到列表末尾，elem 必须大于列表中的所有元素
+// elem 原有的链接会被清除，层数超过列表最大层数时截断。
+func (b *builder[K, V]) append(elem *Element[K, V]) {
+	list := b.list
+	level := elem.Level()
+
+	if max := len(list.levels); level > max {
+		elem.levels = elem.levels[:max]
+		elem.spans = elem.spans[:max]
+		level = max
+	}
+
+	rank := list.length + 1
+	elem.list = list
+	elem.prev = list.back
+	elem.prevTopLevel = list.headerElement(b.tails[level-1])
+
+	for i := 0; i < level; i++ {
+		tail := b.tails[i]
+		tail.levels[i] = elem
+		tail.spans[i] = rank - b.ranks[i]
+		elem.levels[i] = nil
+		elem.spans[i] = 0
+		b.tails[i] = &elem.elementHeader
+		b.ranks[i] = rank
+	}
+
+	list.back = elem
+	list.length++
+}
+
+// sortedLevel 返回排名为 rank（从 1 开始）的元素在平衡跳表中的层数
+// 第 base^k 的倍数个元素有 k+1 层，层数不超过 max。
+func sortedLevel(rank, base, max int) int {
+	level := 1
+	for level < max && rank%base == 0 {
+		rank /= base
+		level++
+	}
+	return level
+}
+
+// FromSorted 清空列表，并按 seq 的顺序以 O(n) 的时间重新构建。
+// seq 的 key 必须从小到大排列，相邻的 key 相等时保留后一个值。
+// 元素的层数是确定的：每一层的元素间隔为提升概率的倒数（取整，至少为 2），
+// 与随机层数的期望分布相同，且不超过 SetMaxLevel 设置的最大层数。
+// key 没有按顺序排列时返回 ErrNotSorted，列表被清空。
+func (list *SkipList[K, V]) FromSorted(seq iter.Seq2[K, V]) error {
+	b := newBuilder(list)
+	base := max(2, int(math.Round((1<<31)/float64((1<<31)-int64(list.stopBelow)))))
+
+	for key, value := range seq {
+		score := list.calcScore(key)
+
+		if back := list.back; back != nil {
+			comp := list.compare(score, key, back)
+
+			if comp == 0 {
+				back.Value = value
+				continue
+			}
+
+			if comp < 0 {
+				list.Init()
+				return ErrNotSorted
+			}
+		}
+
+		b.append(newElement(list, sortedLevel(list.length+1, base, list.maxLevel), score, key, value))
+	}
+
+	return nil
+}
+
+// Merge 把 other 的所有元素移动到 list 中，合并后 other 为空。
+// 时间复杂度为 O(n+m)，元素保留原来的层数，other 中的元素对象直接移入 list。
+// key 相同时保留 other 的元素，list 中原来的元素被删除。
+// other 必须与 list 使用相同的顺序。
+func (list *SkipList[K, V]) Merge(other *SkipList[K, V]) {
+	if other == nil || other == list || other.length == 0 {
+		return
+	}
+
+	a, o := list.Front(), other.Front()
+	other.Init()
+	b := newBuilder(list)
+
+	for a != nil || o != nil {
+		var elem *Element[K, V]
+
+		switch {
+		case o == nil:
+			elem, a = a, a.Next()
+		case a == nil:
+			elem, o = o, o.Next()
+		default:
+			comp := list.compare(o.score, o.key, a)
+
+			if comp <= 0 {
+				elem, o = o, o.Next()
+			} else {
+				elem, a = a, a.Next()
+			}
+
+			if comp == 0 {
+				next := a.Next()
+				list.release(a)
+				a = next
+			}
+		}
+
+		b.append(elem)
+	}
+}
+
+// Split 把大于或等于 key 的元素切分到一个新的列表中返回，list 只保留小于 key 的元素。
+// 只需要在每一层断开一次链接，时间复杂度为 O(log n + m)，m 为新列表的长度，
+// 其中 O(m) 用于更新元素所属的列表。
+// 新列表使用与 list 相同的顺序和最大层数。
+func (list *SkipList[K, V]) Split(key K) *SkipList[K, V] {
+	right := list.emptyCopy()
+
+	score := list.calcScore(key)
+	prevHeader := &list.elementHeader
+	rank := 0
+
+	// 每一层中最后一个小于 key 的节点及其排名
+	max := len(list.levels)
+
+	var maxStaticAllocElemHeaders [preallocDefaultMaxLevel]*elementHeader[K, V]
+	var maxStaticAllocRanks [preallocDefaultMaxLevel]int
+	var prevElemHeaders []*elementHeader[K, V]
+	var prevRanks []int
+
+	if max <= preallocDefaultMaxLevel {
+		prevElemHeaders = maxStaticAllocElemHeaders[:max]
+		prevRanks = maxStaticAllocRanks[:max]
+	} else {
+		prevElemHeaders = make([]*elementHeader[K, V], max)
+		prevRanks = make([]int, max)
+	}
+
+	for i := max - 1; i >= 0; i-- {
+		for next := prevHeader.levels[i]; next != nil && list.compare(score, key, next) > 0; next = prevHeader.levels[i] {
+			rank += prevHeader.spans[i]
+			prevHeader = &next.elementHeader
+		}
+
+		prevElemHeaders[i] = prevHeader
+		prevRanks[i] = rank
+	}
+
+	first := prevHeader.levels[0]
+
+	if first == nil {
+		return right
+	}
+
+	// first 的排名为 rank+1，在新列表中为 1
+	for i := 0; i < max; i++ {
+		prev := prevElemHeaders[i]
+		next := prev.levels[i]
+
+		if next == nil {
+			continue
+		}
+
+		right.levels[i] = next
+		right.spans[i] = prevRanks[i] + prev.spans[i] - rank
+		prev.levels[i] = nil
+
+		if next.Level() == i+1 {
+			next.prevTopLevel = nil
+		}
+	}
+
+	first.prev = nil
+	right.back = list.back
+	right.length = list.length - rank
+	list.back = list.headerElement(prevElemHeaders[0])
+	list.length = rank
+
+	for elem := first; elem != nil; elem = elem.Next() {
+		elem.list = right
+	}
+
+	return right
+}
@@ -0,0 +1,183 @@
+package skipList
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkLinks 校验 prev、prevTopLevel、back 以及元素所属的列表，并调用 checkSpans
+func checkLinks[K, V any](t *testing.T, list *SkipList[K, V]) {
+	t.Helper()
+	checkSpans(t, list)
+
+	last := make([]*Element[K, V], len(list.levels))
+	var prev *Element[K, V]
+
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		level := elem.Level()
+
+		if elem.list != list {
+			t.Fatalf("key %v 不属于该列表", elem.Key())
+		}
+		if elem.prev != prev {
+			t.Fatalf("key %v 的 prev 错误", elem.Key())
+		}
+		if elem.prevTopLevel != last[level-1] {
+			t.Fatalf("key %v 的 prevTopLevel 错误", elem.Key())
+		}
+		if prev != nil && list.compare(elem.score, elem.key, prev) <= 0 {
+			t.Fatalf("key %v 没有大于前一个元素", elem.Key())
+		}
+
+		for i := 0; i < level; i++ {
+			if last[i] == nil && list.levels[i] != elem || last[i] != nil && last[i].levels[i] != elem {
+				t.Fatalf("key %v 第 %d 层的链接错误", elem.Key(), i)
+			}
+			last[i] = elem
+		}
+
+		prev = elem
+	}
+
+	if list.Back() != prev {
+		t.Fatalf("back 错误")
+	}
+
+	for i, elem := range last {
+		if elem != nil && elem.levels[i] != nil {
+			t.Fatalf("第 %d 层的最后一个元素后面还有元素", i)
+		}
+	}
+}
+
+func sortedSeq(keys []int) func(yield func(int, int) bool) {
+	return func(yield func(int, int) bool) {
+		for _, k := range keys {
+			if !yield(k, k*10) {
+				return
+			}
+		}
+	}
+}
+
+func TestFromSorted(t *testing.T) {
+	a := assert.New(t)
+	list := New[int, int]()
+	list.Set(100, 0)
+
+	keys := []int{1, 2, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+	a.NoError(list.FromSorted(sortedSeq(keys)))
+	a.Equal(10, list.Len())
+	a.Equal([]int{1, 2, 3, 5, 8, 13, 21, 34, 55, 89}, collectKeys(list.All()))
+	a.Equal(4, list.GetByRank(7).Level())
+	checkLinks(t, list)
+
+	// 构建后可以继续修改
+	list.Set(4, 40)
+	list.Remove(13)
+	checkLinks(t, list)
+
+	a.ErrorIs(list.FromSorted(sortedSeq([]int{1, 3, 2})), ErrNotSorted)
+	a.Equal(0, list.Len())
+
+	// 层数按提升概率计算
+	a.NoError(list.SetProbability(0.25))
+	a.NoError(list.FromSorted(sortedSeq([]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})))
+	a.Equal([]int{1, 1, 1, 2, 1, 1, 1, 2, 1, 1, 1, 2, 1, 1, 1, 3}, levelsOf(list))
+	checkLinks(t, list)
+
+	list.SetMaxLevel(2)
+	a.NoError(list.FromSorted(func(yield func(int, int) bool) {
+		for i := 0; i < 1000 && yield(i, i); i++ {
+		}
+	}))
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		a.LessOrEqual(elem.Level(), 2)
+	}
+	a.Equal(2, list.GetByRank(15).Level())
+	checkLinks(t, list)
+}
+
+func TestMergeSplit(t *testing.T) {
+	a := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+
+	for round := 0; round < 50; round++ {
+		left, right := New[int, int](), New[int, int]()
+		model := map[int]int{}
+
+		for i := 0; i < r.Intn(200); i++ {
+			k := r.Intn(300)
+			left.Set(k, 1)
+			model[k] = 1
+		}
+		for i := 0; i < r.Intn(200); i++ {
+			k := r.Intn(300)
+			right.Set(k, 2)
+			model[k] = 2
+		}
+
+		left.Merge(right)
+		a.Equal(0, right.Len())
+		checkLinks(t, left)
+		checkLinks(t, right)
+
+		keys := slices.Sorted(maps.Keys(model))
+		a.Equal(len(keys), len(collectKeys(left.All())))
+		for k, v := range left.All() {
+			a.Equal(model[k], v)
+		}
+
+		pivot := r.Intn(320) - 10
+		upper := left.Split(pivot)
+		checkLinks(t, left)
+		checkLinks(t, upper)
+
+		i, _ := slices.BinarySearch(keys, pivot)
+		if len(keys[:i]) == 0 {
+			a.Empty(collectKeys(left.All()))
+		} else {
+			a.Equal(keys[:i], collectKeys(left.All()))
+		}
+		if len(keys[i:]) == 0 {
+			a.Empty(collectKeys(upper.All()))
+		} else {
+			a.Equal(keys[i:], collectKeys(upper.All()))
+		}
+
+		// 切分后的两个列表都可以继续修改，再合并回去
+		upper.Set(pivot, 3)
+		left.Set(pivot-1, 3)
+		upper.RemoveFront()
+		checkLinks(t, upper)
+		left.Merge(upper)
+		checkLinks(t, left)
+	}
+}
+
+func BenchmarkFromSorted(b *testing.B) {
+	keys := make([]int, benchSize)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	b.Run("FromSorted", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			list := New[int, int]()
+			list.FromSorted(sortedSeq(keys))
+		}
+	})
+
+	b.Run("Set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			list := New[int, int]()
+			for _, k := range keys {
+				list.Set(k, k)
+			}
+		}
+	})
+}
@@ -0,0 +1,369 @@
+package skipList
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// 二进制格式：
+//
+//	magic "GTSL" | version uint8 | count uvarint | frame... | 0 uint32 | crc32 uint32
+//
+// frame 为 4 字节大端序长度加上一段 gob 数据，所有 frame 拼接起来是一个按 key 从小到大排列的
+// record 的 gob 流。crc32（IEEE）覆盖 crc32 之前的所有字节。
+// 分帧使得读取时不会越过数据的结尾，WriteTo、ReadFrom 可以用于更大的流中。
+
+const (
+	encodingMagic   = "GTSL"
+	encodingVersion = 1
+	// frameSize 一个 frame 的 gob 数据超过 frameSize 后写出
+	frameSize = 32 << 10
+)
+
+var (
+	// ErrInvalidFormat 数据不是 SkipList 的序列化格式或已损坏
+	ErrInvalidFormat = errors.New("skiplist: 序列化数据格式错误")
+	// ErrUnsupportedVersion 序列化数据的版本不受支持
+	ErrUnsupportedVersion = errors.New("skiplist: 不支持的序列化版本")
+	// ErrChecksum 序列化数据的校验和不一致
+	ErrChecksum = errors.New("skiplist: 序列化数据校验和错误")
+	// ErrUninitialized 列表没有通过 New 等函数创建，不知道如何比较 key
+	ErrUninitialized = errors.New("skiplist: 列表必须先通过 New 等函数创建")
+)
+
+// record 序列化时的一个元素
+type record[K, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// jsonList JSON 格式，checksum 为压缩后的 entries 的 crc32
+type jsonList struct {
+	Version  int             `json:"version"`
+	Checksum uint32          `json:"checksum"`
+	Entries  json.RawMessage `json:"entries"`
+}
+
+// countingWriter 统计写出的字节数并计算 crc32
+type countingWriter struct {
+	w   io.Writer
+	crc hash.Hash32
+	n   int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.crc.Write(p[:n])
+	cw.n += int64(n)
+	return n, err
+}
+
+// countingReader 统计读取的字节数并计算 crc32，每次只从 r 读取需要的字节
+type countingReader struct {
+	r   io.Reader
+	crc hash.Hash32
+	n   int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.crc.Write(p[:n])
+	cr.n += int64(n)
+	return n, err
+}
+
+func (cr *countingReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(cr, b[:])
+	return b[0], err
+}
+
+// frameReader 把 frame 中的 gob 数据拼接成一个流，读到长度为 0 的 frame 时返回 io.EOF
+// 实现了 io.ByteReader，gob 直接从中读取而不是再包装一层 bufio.Reader，
+// 因此 Decode 之后未读的数据仍然留在 frameReader 中。
+type frameReader struct {
+	r      io.Reader
+	remain uint32
+	done   bool
+}
+
+func (fr *frameReader) Read(p []byte) (int, error) {
+	for fr.remain == 0 {
+		if fr.done {
+			return 0, io.EOF
+		}
+
+		var size [4]byte
+		if _, err := io.ReadFull(fr.r, size[:]); err != nil {
+			return 0, unexpectedEOF(err)
+		}
+
+		fr.remain = binary.BigEndian.Uint32(size[:])
+		fr.done = fr.remain == 0
+	}
+
+	if uint32(len(p)) > fr.remain {
+		p = p[:fr.remain]
+	}
+
+	n, err := fr.r.Read(p)
+	fr.remain -= uint32(n)
+
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+func (fr *frameReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(fr, b[:]); err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// WriteTo 把列表按 key 从小到大写入 w，返回写入的字节数，实现 io.WriterTo。
+// key 和 value 使用 gob 编码，因此必须是 gob 支持的类型，interface 类型的 key 需要先 gob.Register。
+// 元素的过期时间（见 SetWithTTL）不会被写出，读取后的元素都没有过期时间。
+func (list *SkipList[K, V]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w, crc: crc32.NewIEEE()}
+	header := append([]byte(encodingMagic), encodingVersion)
+	header = binary.AppendUvarint(header, uint64(list.length))
+
+	if _, err := cw.Write(header); err != nil {
+		return cw.n, err
+	}
+
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+
+	flush := func() error {
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(buf.Len()))
+
+		if _, err := cw.Write(size[:]); err != nil {
+			return err
+		}
+
+		_, err := buf.WriteTo(cw)
+		return err
+	}
+
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		if err := enc.Encode(record[K, V]{Key: elem.key, Value: elem.Value}); err != nil {
+			return cw.n, err
+		}
+
+		if buf.Len() >= frameSize {
+			if err := flush(); err != nil {
+				return cw.n, err
+			}
+		}
+	}
+
+	if buf.Len() > 0 {
+		if err := flush(); err != nil {
+			return cw.n, err
+		}
+	}
+
+	// 长度为 0 的 frame 表示结束
+	if err := flush(); err != nil {
+		return cw.n, err
+	}
+
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], cw.crc.Sum32())
+	n, err := w.Write(sum[:])
+	return cw.n + int64(n), err
+}
+
+// ReadFrom 清空列表，并从 r 读取 WriteTo 写出的数据，返回读取的字节数，实现 io.ReaderFrom。
+// 只读取到数据的结尾为止，元素通过 FromSorted 以 O(n) 的时间构建。
+// 出错时列表被清空。
+func (list *SkipList[K, V]) ReadFrom(r io.Reader) (n int64, err error) {
+	if list.compareKey == nil {
+		return 0, ErrUninitialized
+	}
+
+	cr := &countingReader{r: r, crc: crc32.NewIEEE()}
+
+	defer func() {
+		if err != nil {
+			list.Init()
+		}
+	}()
+
+	var header [len(encodingMagic) + 1]byte
+	if _, err = io.ReadFull(cr, header[:]); err != nil {
+		return cr.n, unexpectedEOF(err)
+	}
+
+	if string(header[:len(encodingMagic)]) != encodingMagic {
+		return cr.n, ErrInvalidFormat
+	}
+
+	if header[len(encodingMagic)] != encodingVersion {
+		return cr.n, ErrUnsupportedVersion
+	}
+
+	count, err := binary.ReadUvarint(cr)
+	if err != nil {
+		return cr.n, unexpectedEOF(err)
+	}
+
+	fr := &frameReader{r: cr}
+	dec := gob.NewDecoder(fr)
+	var decodeErr error
+
+	err = list.FromSorted(func(yield func(K, V) bool) {
+		for i := uint64(0); i < count; i++ {
+			var rec record[K, V]
+
+			if decodeErr = dec.Decode(&rec); decodeErr != nil {
+				return
+			}
+
+			if !yield(rec.Key, rec.Value) {
+				return
+			}
+		}
+	})
+
+	if decodeErr != nil {
+		return cr.n, decodeErr
+	}
+
+	if err != nil {
+		return cr.n, err
+	}
+
+	// count 个元素之后必须正好是结束标记
+	if extra, _ := io.Copy(io.Discard, fr); extra > 0 || !fr.done {
+		return cr.n, ErrInvalidFormat
+	}
+
+	want := cr.crc.Sum32()
+
+	var sum [4]byte
+	if _, err = io.ReadFull(cr, sum[:]); err != nil {
+		return cr.n, unexpectedEOF(err)
+	}
+
+	if binary.BigEndian.Uint32(sum[:]) != want {
+		return cr.n, ErrChecksum
+	}
+
+	return cr.n, nil
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler，格式与 WriteTo 相同。
+// gob 会自动使用 MarshalBinary 和 UnmarshalBinary。
+func (list *SkipList[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+
+	if _, err := list.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，列表必须已经通过 New 等函数创建。
+func (list *SkipList[K, V]) UnmarshalBinary(data []byte) error {
+	n, err := list.ReadFrom(bytes.NewReader(data))
+
+	if err == nil && n != int64(len(data)) {
+		list.Init()
+		return ErrInvalidFormat
+	}
+
+	return err
+}
+
+// MarshalJSON 实现 json.Marshaler，格式为
+//
+//	{"version":1,"checksum":...,"entries":[{"key":...,"value":...},...]}
+//
+// entries 按 key 从小到大排列，checksum 为压缩后的 entries 的 crc32。
+// 与 WriteTo 相同，元素的过期时间不会被写出。
+func (list *SkipList[K, V]) MarshalJSON() ([]byte, error) {
+	entries := make([]record[K, V], 0, list.length)
+
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, record[K, V]{Key: elem.key, Value: elem.Value})
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonList{
+		Version:  encodingVersion,
+		Checksum: crc32.ChecksumIEEE(data),
+		Entries:  data,
+	})
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，列表必须已经通过 New 等函数创建。
+// entries 中的空白不影响校验和。出错时列表被清空。
+func (list *SkipList[K, V]) UnmarshalJSON(data []byte) (err error) {
+	if list.compareKey == nil {
+		return ErrUninitialized
+	}
+
+	defer func() {
+		if err != nil {
+			list.Init()
+		}
+	}()
+
+	var l jsonList
+	if err = json.Unmarshal(data, &l); err != nil {
+		return err
+	}
+
+	if l.Version != encodingVersion {
+		return ErrUnsupportedVersion
+	}
+
+	var compact bytes.Buffer
+	if err = json.Compact(&compact, l.Entries); err != nil {
+		return ErrInvalidFormat
+	}
+
+	if crc32.ChecksumIEEE(compact.Bytes()) != l.Checksum {
+		return ErrChecksum
+	}
+
+	var entries []record[K, V]
+	if err = json.Unmarshal(compact.Bytes(), &entries); err != nil {
+		return err
+	}
+
+	return list.FromSorted(func(yield func(K, V) bool) {
+		for _, entry := range entries {
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	})
+}
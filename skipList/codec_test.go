@@ -0,0 +1,163 @@
+package skipList
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCodecList(n int) *SkipList[int, string] {
+	list := New[int, string]()
+	for i := 0; i < n; i++ {
+		list.Set(i*3, fmt.Sprint("v", i))
+	}
+	return list
+}
+
+func TestBinary(t *testing.T) {
+	a := assert.New(t)
+
+	for _, n := range []int{0, 1, 10, 20000} {
+		list := newCodecList(n)
+		data, err := list.MarshalBinary()
+		a.NoError(err)
+
+		restored := New[int, string]()
+		restored.Set(-1, "stale")
+		a.NoError(restored.UnmarshalBinary(data))
+		a.Equal(collectKeys(list.All()), collectKeys(restored.All()))
+		a.Equal(collectValues(list.All()), collectValues(restored.All()))
+		checkLinks(t, restored)
+	}
+}
+
+func TestBinary_Stream(t *testing.T) {
+	a := assert.New(t)
+	first, second := newCodecList(5000), newCodecList(7)
+
+	var buf bytes.Buffer
+	n1, err := first.WriteTo(&buf)
+	a.NoError(err)
+	n2, err := second.WriteTo(&buf)
+	a.NoError(err)
+	buf.WriteString("tail")
+	a.Equal(int64(buf.Len()-4), n1+n2)
+
+	// ReadFrom 不会读取超过数据结尾的字节
+	list := New[int, string]()
+	n, err := list.ReadFrom(&buf)
+	a.NoError(err)
+	a.Equal(n1, n)
+	a.Equal(5000, list.Len())
+
+	n, err = list.ReadFrom(&buf)
+	a.NoError(err)
+	a.Equal(n2, n)
+	a.Equal(7, list.Len())
+
+	rest, _ := io.ReadAll(&buf)
+	a.Equal("tail", string(rest))
+}
+
+func TestBinary_Corrupted(t *testing.T) {
+	a := assert.New(t)
+	data, err := newCodecList(100).MarshalBinary()
+	a.NoError(err)
+	list := New[int, string]()
+
+	a.ErrorIs(list.UnmarshalBinary([]byte("NOPE\x01\x00")), ErrInvalidFormat)
+	a.ErrorIs(list.UnmarshalBinary(append([]byte("GTSL\x09"), data[5:]...)), ErrUnsupportedVersion)
+	a.ErrorIs(list.UnmarshalBinary(data[:len(data)-2]), io.ErrUnexpectedEOF)
+	a.ErrorIs(list.UnmarshalBinary(append(bytes.Clone(data), 0)), ErrInvalidFormat)
+
+	// count 少于实际的元素个数时，多出的元素不会被忽略
+	fewer := bytes.Clone(data)
+	fewer[5]--
+	a.ErrorIs(list.UnmarshalBinary(fewer), ErrInvalidFormat)
+
+	corrupted := bytes.Clone(data)
+	corrupted[len(corrupted)-1] ^= 0xff
+	a.ErrorIs(list.UnmarshalBinary(corrupted), ErrChecksum)
+	a.Equal(0, list.Len())
+
+	// 任意一个字节损坏都会返回错误
+	for i := range data {
+		corrupted := bytes.Clone(data)
+		corrupted[i] ^= 0x55
+		a.Error(list.UnmarshalBinary(corrupted), "byte %d", i)
+	}
+
+	var zero SkipList[int, string]
+	a.ErrorIs(zero.UnmarshalBinary(data), ErrUninitialized)
+}
+
+func TestBinary_DropsTTL(t *testing.T) {
+	a := assert.New(t)
+	list := New[int, string]()
+	list.SetWithTTL(1, "a", time.Hour)
+	data, err := list.MarshalBinary()
+	a.NoError(err)
+
+	restored := New[int, string]()
+	a.NoError(restored.UnmarshalBinary(data))
+	a.Equal("a", restored.Get(1).Value)
+	a.True(restored.Get(1).ExpireAt().IsZero())
+}
+
+func TestGob(t *testing.T) {
+	a := assert.New(t)
+
+	type snapshot struct {
+		Name  string
+		Index *SkipList[string, int]
+	}
+
+	index := New[string, int]()
+	index.Set("b", 2)
+	index.Set("a", 1)
+
+	var buf bytes.Buffer
+	a.NoError(gob.NewEncoder(&buf).Encode(snapshot{Name: "idx", Index: index}))
+
+	restored := snapshot{Index: New[string, int]()}
+	a.NoError(gob.NewDecoder(&buf).Decode(&restored))
+	a.Equal("idx", restored.Name)
+	a.Equal([]string{"a", "b"}, collectKeys(restored.Index.All()))
+}
+
+func TestJSON(t *testing.T) {
+	a := assert.New(t)
+	list := newCodecList(3)
+
+	data, err := json.Marshal(list)
+	a.NoError(err)
+	a.Contains(string(data), `"entries":[{"key":0,"value":"v0"},{"key":3,"value":"v1"},{"key":6,"value":"v2"}]`)
+
+	restored := New[int, string]()
+	a.NoError(json.Unmarshal(data, restored))
+	a.Equal([]int{0, 3, 6}, collectKeys(restored.All()))
+
+	// 格式化后的 JSON 校验和不变
+	indented, err := json.MarshalIndent(list, "", "  ")
+	a.NoError(err)
+	a.NoError(json.Unmarshal(indented, restored))
+	a.Equal(3, restored.Len())
+
+	tampered := bytes.Replace(data, []byte(`"v1"`), []byte(`"v9"`), 1)
+	a.ErrorIs(json.Unmarshal(tampered, restored), ErrChecksum)
+	a.Equal(0, restored.Len())
+
+	unsorted := bytes.Replace(data, []byte(`"key":3`), []byte(`"key":9`), 1)
+	var l jsonList
+	a.NoError(json.Unmarshal(unsorted, &l))
+	l.Checksum = crc32.ChecksumIEEE(l.Entries)
+	fixed, _ := json.Marshal(l)
+	a.ErrorIs(json.Unmarshal(fixed, restored), ErrNotSorted)
+}
@@ -1,5 +1,11 @@
 package skipList
 
+import (
+	"cmp"
+	"math/big"
+	"time"
+)
+
 /*
  * 说明：
  * 作者：吕元龙
@@ -7,21 +13,176 @@ package skipList
  */
 
 // Comparable 比较接口
-type Comparable interface {
+// CalcScore 必须与 Compare 的顺序一致：分数不同的两个 key，分数小的 Compare 也更小。
+type Comparable[K any] interface {
 	// Compare 比较两个对象，返回-1，0，1
-	Compare(a, b interface{}) int
+	Compare(a, b K) int
 	// CalcScore 计算一个对象的分数
-	CalcScore(key interface{}) float64
+	CalcScore(key K) float64
 }
 
-type ComparableFunc func(a, b interface{}) int
+// ComparableFunc 使用函数比较 key，没有分数
+type ComparableFunc[K any] func(a, b K) int
 
 // Compare 实现Comparable
-func (c ComparableFunc) Compare(a, b interface{}) int {
+func (c ComparableFunc[K]) Compare(a, b K) int {
 	return c(a, b)
 }
 
 // CalcScore 不知道c中func的实现，所以返回0
-func (c ComparableFunc) CalcScore(key interface{}) float64 {
+func (c ComparableFunc[K]) CalcScore(key K) float64 {
+	return 0
+}
+
+// LessThanFunc 使用 less 创建 ComparableFunc
+// less(a, b) 和 less(b, a) 都不成立时认为 a 等于 b。
+func LessThanFunc[K any](less func(a, b K) bool) ComparableFunc[K] {
+	return func(a, b K) int {
+		if less(a, b) {
+			return -1
+		}
+
+		if less(b, a) {
+			return 1
+		}
+
+		return 0
+	}
+}
+
+type reverseComparable[K any] struct {
+	c Comparable[K]
+}
+
+// Reverse 返回与 c 顺序相反的 Comparable，分数取反
+func Reverse[K any](c Comparable[K]) Comparable[K] {
+	if r, ok := c.(reverseComparable[K]); ok {
+		return r.c
+	}
+
+	return reverseComparable[K]{c: c}
+}
+
+func (r reverseComparable[K]) Compare(a, b K) int {
+	return r.c.Compare(b, a)
+}
+
+func (r reverseComparable[K]) CalcScore(key K) float64 {
+	return -r.c.CalcScore(key)
+}
+
+type fieldComparable[K, F any] struct {
+	field func(key K) F
+	c     Comparable[F]
+}
+
+// Field 按 key 的一个字段比较，field 返回用于比较的字段，分数为该字段的分数
+func Field[K, F any](field func(key K) F, c Comparable[F]) Comparable[K] {
+	return fieldComparable[K, F]{field: field, c: c}
+}
+
+func (f fieldComparable[K, F]) Compare(a, b K) int {
+	return f.c.Compare(f.field(a), f.field(b))
+}
+
+func (f fieldComparable[K, F]) CalcScore(key K) float64 {
+	return f.c.CalcScore(f.field(key))
+}
+
+type compositeComparable[K any] []Comparable[K]
+
+// Composite 按 cs 的顺序依次比较，前一个相等时才比较下一个，用于元组或多字段的 key
+// 分数为第一个 Comparable 的分数。
+//
+//	NewComparable[Event, V](Composite(
+//		Field(func(e Event) time.Time { return e.At }, Time),
+//		Field(func(e Event) uint64 { return e.ID }, Number[uint64]{}),
+//	))
+func Composite[K any](cs ...Comparable[K]) Comparable[K] {
+	return compositeComparable[K](cs)
+}
+
+func (cs compositeComparable[K]) Compare(a, b K) int {
+	for _, c := range cs {
+		if result := c.Compare(a, b); result != 0 {
+			return result
+		}
+	}
+
 	return 0
 }
+
+func (cs compositeComparable[K]) CalcScore(key K) float64 {
+	if len(cs) == 0 {
+		return 0
+	}
+
+	return cs[0].CalcScore(key)
+}
+
+// number 可以无损保持顺序转换为 float64 的类型
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Number 数字类型的 Comparable，分数为 key 的值
+// 超过 2^53 的整数转换为分数时会丢失精度，但分数相同时仍会按 Compare 精确比较。
+type Number[K number] struct{}
+
+func (Number[K]) Compare(a, b K) int {
+	return cmp.Compare(a, b)
+}
+
+func (Number[K]) CalcScore(key K) float64 {
+	return float64(key)
+}
+
+type scorableComparable[K Scorable] struct {
+	tiebreak func(a, b K) int
+}
+
+// ByScore 按 Score() 比较 key，分数相同时调用 tiebreak，tiebreak 为 nil 时认为两个 key 相等
+func ByScore[K Scorable](tiebreak func(a, b K) int) Comparable[K] {
+	return scorableComparable[K]{tiebreak: tiebreak}
+}
+
+func (s scorableComparable[K]) Compare(a, b K) int {
+	if result := cmp.Compare(a.Score(), b.Score()); result != 0 || s.tiebreak == nil {
+		return result
+	}
+
+	return s.tiebreak(a, b)
+}
+
+func (s scorableComparable[K]) CalcScore(key K) float64 {
+	return key.Score()
+}
+
+type timeComparable struct{}
+
+// Time time.Time 的 Comparable，分数为 Unix 时间（秒），精确到纳秒的比较由 Compare 完成
+var Time Comparable[time.Time] = timeComparable{}
+
+func (timeComparable) Compare(a, b time.Time) int {
+	return a.Compare(b)
+}
+
+func (timeComparable) CalcScore(key time.Time) float64 {
+	return float64(key.Unix()) + float64(key.Nanosecond())/1e9
+}
+
+type bigIntComparable struct{}
+
+// BigInt *big.Int 的 Comparable，分数为最接近的 float64
+var BigInt Comparable[*big.Int] = bigIntComparable{}
+
+func (bigIntComparable) Compare(a, b *big.Int) int {
+	return a.Cmp(b)
+}
+
+func (bigIntComparable) CalcScore(key *big.Int) float64 {
+	score, _ := key.Float64()
+	return score
+}
@@ -0,0 +1,342 @@
+package skipList
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// concurrentMaxLevel ConcurrentSkipList 的最大层数
+const concurrentMaxLevel = 32
+
+// ConcurrentSkipList 并发安全的跳表
+// 实现为 lazy skip list（Herlihy 等，A Simple Optimistic Skiplist Algorithm）：
+//   - Get 不加锁，只读取原子指针
+//   - Set、Remove 先无锁查找，再锁住每一层的前置节点并校验，校验失败时重试
+//   - 删除时先标记节点（逻辑删除），再从每一层摘除（物理删除）
+//
+// Set、Get、Remove 都是线性一致的，遍历是弱一致的：
+// 遍历期间的修改可能可见也可能不可见，但不会重复或遗漏遍历开始前就存在且未被修改的元素。
+type ConcurrentSkipList[K, V any] struct {
+	head    *concurrentNode[K, V]
+	compare func(a, b K) int
+	length  atomic.Int64
+}
+
+type concurrentNode[K, V any] struct {
+	key      K
+	val      atomic.Pointer[V]
+	next     []atomic.Pointer[concurrentNode[K, V]]
+	mu       sync.Mutex
+	marked   atomic.Bool // 已被逻辑删除
+	linked   atomic.Bool // 已接入所有层
+	topLevel int
+}
+
+func newConcurrentNode[K, V any](key K, val V, level int) *concurrentNode[K, V] {
+	n := &concurrentNode[K, V]{
+		key:      key,
+		next:     make([]atomic.Pointer[concurrentNode[K, V]], level),
+		topLevel: level,
+	}
+	n.val.Store(&val)
+	return n
+}
+
+// NewConcurrent 创建一个 key 按自然顺序排列的 ConcurrentSkipList
+func NewConcurrent[K cmp.Ordered, V any]() *ConcurrentSkipList[K, V] {
+	return NewConcurrentFunc[K, V](cmp.Compare[K])
+}
+
+// NewConcurrentFunc 创建一个使用 compare 比较 key 的 ConcurrentSkipList
+func NewConcurrentFunc[K, V any](compare func(a, b K) int) *ConcurrentSkipList[K, V] {
+	head := &concurrentNode[K, V]{
+		next:     make([]atomic.Pointer[concurrentNode[K, V]], concurrentMaxLevel),
+		topLevel: concurrentMaxLevel,
+	}
+	head.linked.Store(true)
+	return &ConcurrentSkipList[K, V]{
+		head:    head,
+		compare: compare,
+	}
+}
+
+// randLevel 以 1/2 的概率逐层提升，math/rand/v2 的全局函数是并发安全的
+func (list *ConcurrentSkipList[K, V]) randLevel() int {
+	return 1 + bits.TrailingZeros64(rand.Uint64()|1<<(concurrentMaxLevel-1))
+}
+
+// find 查找 key 在每一层的前置节点和后继节点，返回 key 所在节点的最高层，不存在时返回 -1
+func (list *ConcurrentSkipList[K, V]) find(key K, preds, succs *[concurrentMaxLevel]*concurrentNode[K, V]) int {
+	found := -1
+	pred := list.head
+
+	for level := concurrentMaxLevel - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+
+		for curr != nil && list.compare(curr.key, key) < 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+
+		if found == -1 && curr != nil && list.compare(curr.key, key) == 0 {
+			found = level
+		}
+
+		preds[level] = pred
+		succs[level] = curr
+	}
+
+	return found
+}
+
+// unlockPreds 释放第 0 层到第 highestLocked 层锁住的前置节点，同一个节点只释放一次
+func unlockPreds[K, V any](preds *[concurrentMaxLevel]*concurrentNode[K, V], highestLocked int) {
+	var prev *concurrentNode[K, V]
+
+	for level := 0; level <= highestLocked; level++ {
+		if pred := preds[level]; pred != prev {
+			pred.mu.Unlock()
+			prev = pred
+		}
+	}
+}
+
+// Len 返回元素个数
+func (list *ConcurrentSkipList[K, V]) Len() int {
+	return int(list.length.Load())
+}
+
+// Get 返回 key 对应的值，不加锁
+func (list *ConcurrentSkipList[K, V]) Get(key K) (val V, ok bool) {
+	pred := list.head
+
+	for level := concurrentMaxLevel - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+
+		for curr != nil && list.compare(curr.key, key) < 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+
+		if curr != nil && list.compare(curr.key, key) == 0 {
+			if curr.linked.Load() && !curr.marked.Load() {
+				return *curr.val.Load(), true
+			}
+
+			return
+		}
+	}
+
+	return
+}
+
+// Contains 返回 key 是否存在
+func (list *ConcurrentSkipList[K, V]) Contains(key K) bool {
+	_, ok := list.Get(key)
+	return ok
+}
+
+// Set 设置 key 对应的值，返回 key 是否是新加入的
+func (list *ConcurrentSkipList[K, V]) Set(key K, val V) (added bool) {
+	topLevel := list.randLevel()
+	var preds, succs [concurrentMaxLevel]*concurrentNode[K, V]
+
+	for {
+		if found := list.find(key, &preds, &succs); found != -1 {
+			node := succs[found]
+
+			if node.marked.Load() {
+				// 节点正在被删除，等删除完成后重试
+				runtime.Gosched()
+				continue
+			}
+
+			for !node.linked.Load() {
+				runtime.Gosched()
+			}
+
+			// 加锁保证不会更新一个已经被删除的节点
+			node.mu.Lock()
+			if node.marked.Load() {
+				node.mu.Unlock()
+				continue
+			}
+			node.val.Store(&val)
+			node.mu.Unlock()
+			return false
+		}
+
+		highestLocked := -1
+		valid := true
+		var prev *concurrentNode[K, V]
+
+		for level := 0; valid && level < topLevel; level++ {
+			pred, succ := preds[level], succs[level]
+
+			if pred != prev {
+				pred.mu.Lock()
+				highestLocked = level
+				prev = pred
+			}
+
+			valid = !pred.marked.Load() && (succ == nil || !succ.marked.Load()) && pred.next[level].Load() == succ
+		}
+
+		if !valid {
+			unlockPreds(&preds, highestLocked)
+			continue
+		}
+
+		node := newConcurrentNode(key, val, topLevel)
+
+		for level := 0; level < topLevel; level++ {
+			node.next[level].Store(succs[level])
+		}
+
+		for level := 0; level < topLevel; level++ {
+			preds[level].next[level].Store(node)
+		}
+
+		node.linked.Store(true)
+		unlockPreds(&preds, highestLocked)
+		list.length.Add(1)
+		return true
+	}
+}
+
+// Remove 删除 key，返回被删除的值
+func (list *ConcurrentSkipList[K, V]) Remove(key K) (val V, ok bool) {
+	var preds, succs [concurrentMaxLevel]*concurrentNode[K, V]
+	var victim *concurrentNode[K, V]
+	marked := false
+
+	for {
+		found := list.find(key, &preds, &succs)
+
+		if !marked {
+			if found == -1 {
+				return
+			}
+
+			victim = succs[found]
+
+			// 只删除已经完全接入、且在最高层被找到的节点
+			if !victim.linked.Load() || victim.topLevel-1 != found || victim.marked.Load() {
+				return
+			}
+
+			victim.mu.Lock()
+
+			if victim.marked.Load() {
+				victim.mu.Unlock()
+				return
+			}
+
+			victim.marked.Store(true)
+			marked = true
+		}
+
+		highestLocked := -1
+		valid := true
+		var prev *concurrentNode[K, V]
+
+		for level := 0; valid && level < victim.topLevel; level++ {
+			pred := preds[level]
+
+			if pred != prev {
+				pred.mu.Lock()
+				highestLocked = level
+				prev = pred
+			}
+
+			valid = !pred.marked.Load() && pred.next[level].Load() == victim
+		}
+
+		if !valid {
+			unlockPreds(&preds, highestLocked)
+			continue
+		}
+
+		for level := victim.topLevel - 1; level >= 0; level-- {
+			preds[level].next[level].Store(victim.next[level].Load())
+		}
+
+		victim.mu.Unlock()
+		unlockPreds(&preds, highestLocked)
+		list.length.Add(-1)
+		return *victim.val.Load(), true
+	}
+}
+
+// seek 返回第一个大于或等于 key 的节点（可能已被标记删除）
+func (list *ConcurrentSkipList[K, V]) seek(key K) *concurrentNode[K, V] {
+	pred := list.head
+
+	for level := concurrentMaxLevel - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+
+		for curr != nil && list.compare(curr.key, key) < 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+	}
+
+	return pred.next[0].Load()
+}
+
+// seq 从 start 开始沿第 0 层遍历，跳过已被删除或尚未完全接入的节点
+func (list *ConcurrentSkipList[K, V]) seq(start func() *concurrentNode[K, V], in func(key K) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := start(); node != nil; node = node.next[0].Load() {
+			if in != nil && !in(node.key) {
+				return
+			}
+
+			if !node.linked.Load() || node.marked.Load() {
+				continue
+			}
+
+			if !yield(node.key, *node.val.Load()) {
+				return
+			}
+		}
+	}
+}
+
+// All 按 key 从小到大遍历所有元素，弱一致
+func (list *ConcurrentSkipList[K, V]) All() iter.Seq2[K, V] {
+	return list.seq(func() *concurrentNode[K, V] {
+		return list.head.next[0].Load()
+	}, nil)
+}
+
+// Ascend 从大于或等于 from 的第一个元素开始，按 key 从小到大遍历，弱一致
+func (list *ConcurrentSkipList[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return list.seq(func() *concurrentNode[K, V] {
+		return list.seek(from)
+	}, nil)
+}
+
+// Range 按 key 从小到大遍历 [from, to] 内的元素，flags 可以排除端点，弱一致
+func (list *ConcurrentSkipList[K, V]) Range(from, to K, flags RangeFlag) iter.Seq2[K, V] {
+	return list.seq(func() *concurrentNode[K, V] {
+		node := list.seek(from)
+
+		for flags&ExcludeFrom != 0 && node != nil && list.compare(node.key, from) == 0 {
+			node = node.next[0].Load()
+		}
+
+		return node
+	}, func(key K) bool {
+		if flags&ExcludeTo != 0 {
+			return list.compare(key, to) < 0
+		}
+
+		return list.compare(key, to) <= 0
+	})
+}
@@ -0,0 +1,206 @@
+package skipList
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkConcurrent 在没有并发修改时检查结构：每一层有序、不含已删除节点，第 0 层长度等于 Len
+func checkConcurrent[K, V any](t *testing.T, list *ConcurrentSkipList[K, V]) {
+	t.Helper()
+
+	for level := 0; level < concurrentMaxLevel; level++ {
+		count := 0
+		var prev *concurrentNode[K, V]
+
+		for node := list.head.next[level].Load(); node != nil; node = node.next[level].Load() {
+			if node.marked.Load() || !node.linked.Load() {
+				t.Fatalf("level %d: unexpected node state marked=%v linked=%v", level, node.marked.Load(), node.linked.Load())
+			}
+
+			if prev != nil && list.compare(prev.key, node.key) >= 0 {
+				t.Fatalf("level %d: keys out of order", level)
+			}
+
+			prev = node
+			count++
+		}
+
+		if level == 0 && count != list.Len() {
+			t.Fatalf("level 0 has %d nodes, Len() = %d", count, list.Len())
+		}
+	}
+}
+
+func TestConcurrent_Basic(t *testing.T) {
+	a := assert.New(t)
+	list := NewConcurrent[int, string]()
+
+	a.True(list.Set(2, "b"))
+	a.True(list.Set(1, "a"))
+	a.True(list.Set(3, "c"))
+	a.False(list.Set(2, "B"))
+	a.Equal(3, list.Len())
+
+	val, ok := list.Get(2)
+	a.True(ok)
+	a.Equal("B", val)
+	a.False(list.Contains(4))
+
+	a.Equal([]int{1, 2, 3}, collectKeys(list.All()))
+	a.Equal([]int{2, 3}, collectKeys(list.Ascend(2)))
+	a.Equal([]int{1, 2}, collectKeys(list.Range(0, 3, ExcludeTo)))
+	a.Equal([]int{2, 3}, collectKeys(list.Range(1, 3, ExcludeFrom)))
+
+	val, ok = list.Remove(2)
+	a.True(ok)
+	a.Equal("B", val)
+	_, ok = list.Remove(2)
+	a.False(ok)
+	a.Equal(2, list.Len())
+
+	// 遍历时删除当前元素
+	for k := range list.All() {
+		list.Remove(k)
+	}
+	a.Equal(0, list.Len())
+	checkConcurrent(t, list)
+}
+
+// TestConcurrent_Disjoint 每个 goroutine 只操作自己的 key，因此可以用本地 map 精确校验每一次操作的结果
+func TestConcurrent_Disjoint(t *testing.T) {
+	const workers = 8
+	const ops = 5000
+	list := NewConcurrent[int, int]()
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			model := map[int]int{}
+
+			for i := 0; i < ops; i++ {
+				key := r.Intn(256)*workers + w
+
+				switch r.Intn(3) {
+				case 0:
+					_, exists := model[key]
+					if added := list.Set(key, i); added == exists {
+						t.Errorf("Set(%d) added=%v, want %v", key, added, !exists)
+						return
+					}
+					model[key] = i
+				case 1:
+					want, exists := model[key]
+					val, ok := list.Remove(key)
+					if ok != exists || val != want {
+						t.Errorf("Remove(%d) = %d, %v, want %d, %v", key, val, ok, want, exists)
+						return
+					}
+					delete(model, key)
+				default:
+					want, exists := model[key]
+					val, ok := list.Get(key)
+					if ok != exists || val != want {
+						t.Errorf("Get(%d) = %d, %v, want %d, %v", key, val, ok, want, exists)
+						return
+					}
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	checkConcurrent(t, list)
+}
+
+// TestConcurrent_Contended 所有 goroutine 争用少量 key，同时进行遍历
+func TestConcurrent_Contended(t *testing.T) {
+	const workers = 8
+	const ops = 5000
+	const keys = 64
+	list := NewConcurrent[int, int]()
+	var wg sync.WaitGroup
+	var added, removed [workers]int
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+
+			for i := 0; i < ops; i++ {
+				key := r.Intn(keys)
+
+				switch r.Intn(4) {
+				case 0, 1:
+					if list.Set(key, key) {
+						added[w]++
+					}
+				case 2:
+					if _, ok := list.Remove(key); ok {
+						removed[w]++
+					}
+				default:
+					if val, ok := list.Get(key); ok && val != key {
+						t.Errorf("Get(%d) = %d", key, val)
+						return
+					}
+				}
+			}
+		}(w)
+	}
+
+	for w := 0; w < 2; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 200; i++ {
+				prev := -1
+				for k := range list.All() {
+					if k <= prev {
+						t.Errorf("iteration out of order: %d after %d", k, prev)
+						return
+					}
+					prev = k
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	total := 0
+	for w := 0; w < workers; w++ {
+		total += added[w] - removed[w]
+	}
+	assert.Equal(t, total, list.Len())
+	checkConcurrent(t, list)
+}
+
+func BenchmarkConcurrent(b *testing.B) {
+	keys := benchKeys()
+	list := NewConcurrent[int, int]()
+	for _, k := range keys {
+		list.Set(k, k)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			k := keys[r.Intn(len(keys))]
+			if r.Intn(10) == 0 {
+				list.Set(k, k)
+			} else {
+				list.Get(k)
+			}
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package skipList
 
-import "unsafe"
+import (
+	"time"
+	"unsafe"
+)
 
 /*
  * 说明：
@@ -10,41 +13,59 @@ import "unsafe"
 
 // 跳表的节点头
 // 只能
-type elementHeader struct {
-	levels []*Element // Next element at all levels.
+type elementHeader[K, V any] struct {
+	levels []*Element[K, V] // Next element at all levels.
+	// spans[i] 为 levels[i] 跨过的元素个数，即两者在第 0 层的距离
+	// 只有 levels[i] 不为 nil 时才有意义
+	spans []int
 }
 
 // Element list中的节点
-type Element struct {
-	elementHeader
+type Element[K, V any] struct {
+	elementHeader[K, V]
 
-	Value interface{}
-	key   interface{}
+	Value V
+	key   K
 	score float64
+	// expireAt 过期时间（UnixNano），0 表示不过期，见 SetWithTTL
+	expireAt int64
 
-	prev         *Element  // Points to previous adjacent elem.
-	prevTopLevel *Element  // Points to previous element which points to this element's top most level.
-	list         *SkipList // The list contains this elem.
+	prev         *Element[K, V]  // Points to previous adjacent elem.
+	prevTopLevel *Element[K, V]  // Points to previous element which points to this element's top most level.
+	list         *SkipList[K, V] // The list contains this elem.
 }
 
-func (header *elementHeader) Element() *Element {
-	return (*Element)(unsafe.Pointer(header))
+func (header *elementHeader[K, V]) Element() *Element[K, V] {
+	return (*Element[K, V])(unsafe.Pointer(header))
 }
 
-func newElement(list *SkipList, level int, score float64, key, value interface{}) *Element {
-	return &Element{
-		elementHeader: elementHeader{
-			levels: make([]*Element, level),
-		},
-		Value: value,
-		key:   key,
-		score: score,
-		list:  list,
+// newElement 创建一个 level 层的元素，list 开启回收时优先复用空闲的元素
+func newElement[K, V any](list *SkipList[K, V], level int, score float64, key K, value V) *Element[K, V] {
+	var elem *Element[K, V]
+
+	if class := sizeClass(level); list.recycle && class >= 0 && len(list.free[class]) > 0 {
+		free := list.free[class]
+		elem = free[len(free)-1]
+		free[len(free)-1] = nil
+		list.free[class] = free[:len(free)-1]
+		elem.levels = elem.levels[:level]
+		elem.spans = elem.spans[:level]
+		clear(elem.levels)
+		clear(elem.spans)
+	} else {
+		elem = allocElement[K, V](level)
 	}
+
+	elem.Value = value
+	elem.key = key
+	elem.score = score
+	elem.expireAt = 0
+	elem.list = list
+	return elem
 }
 
 // Next 返回下一个 elem.
-func (elem *Element) Next() *Element {
+func (elem *Element[K, V]) Next() *Element[K, V] {
 	if len(elem.levels) == 0 {
 		return nil
 	}
@@ -53,13 +74,13 @@ func (elem *Element) Next() *Element {
 }
 
 // Prev 返回前一个 elem.
-func (elem *Element) Prev() *Element {
+func (elem *Element[K, V]) Prev() *Element[K, V] {
 	return elem.prev
 }
 
 // NextLevel 返回特定级别的下一个元素。
 // 如果 level 无效，则返回 nil
-func (elem *Element) NextLevel(level int) *Element {
+func (elem *Element[K, V]) NextLevel(level int) *Element[K, V] {
 	if level < 0 || level >= len(elem.levels) {
 		return nil
 	}
@@ -69,7 +90,7 @@ func (elem *Element) NextLevel(level int) *Element {
 
 // PrevLevel 返回指向特定级别的此元素的上一个元素。
 // 如果 level 无效，则返回 nil。
-func (elem *Element) PrevLevel(level int) *Element {
+func (elem *Element[K, V]) PrevLevel(level int) *Element[K, V] {
 	if level < 0 || level >= len(elem.levels) {
 		return nil
 	}
@@ -96,24 +117,34 @@ func (elem *Element) PrevLevel(level int) *Element {
 }
 
 // Key 返回 elem 的 key。
-func (elem *Element) Key() interface{} {
+func (elem *Element[K, V]) Key() K {
 	return elem.key
 }
 
 // Score 返回此元素的分数。
-// Skip list 使所有元素按分数从小到大排序。
-func (elem *Element) Score() float64 {
+// 通过 Comparable 创建的 skip list 使所有元素按分数从小到大排序，其他 skip list 中分数总是 0。
+func (elem *Element[K, V]) Score() float64 {
 	return elem.score
 }
 
+// ExpireAt 返回 elem 的过期时间，没有设置过期时间时返回零值。
+func (elem *Element[K, V]) ExpireAt() time.Time {
+	if elem.expireAt == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, elem.expireAt)
+}
+
 // Level 返回此 elem 的级别。
-func (elem *Element) Level() int {
+func (elem *Element[K, V]) Level() int {
 	return len(elem.levels)
 }
 
-func (elem *Element) reset() {
+func (elem *Element[K, V]) reset() {
 	elem.list = nil
 	elem.prev = nil
 	elem.prevTopLevel = nil
 	elem.levels = nil
+	elem.spans = nil
 }
@@ -0,0 +1,166 @@
+package skipList
+
+import (
+	"cmp"
+	"iter"
+	"math"
+)
+
+// MultiSkipList 允许重复 key 的跳表
+// key 相同的元素按插入顺序排列，先插入的在前。
+// 内部以 (key, 插入序号) 为 key 的 SkipList 实现，因此排名、区间等操作与 SkipList 一致。
+// 与 SkipList 一样不是并发安全的。
+type MultiSkipList[K any, V comparable] struct {
+	list *SkipList[multiKey[K], V]
+	seq  uint64
+}
+
+// multiKey 跳表中的 key，key 相同时按插入序号排序
+// 序号从 1 开始，0 和 math.MaxUint64 作为查找时的下界和上界。
+type multiKey[K any] struct {
+	key K
+	seq uint64
+}
+
+const (
+	multiSeqLow  = 0
+	multiSeqHigh = math.MaxUint64
+)
+
+// NewMulti 创建一个 key 按自然顺序排列的 MultiSkipList
+func NewMulti[K cmp.Ordered, V comparable]() *MultiSkipList[K, V] {
+	return NewMultiFunc[K, V](cmp.Compare[K])
+}
+
+// NewMultiFunc 创建一个使用 compare 比较 key 的 MultiSkipList
+func NewMultiFunc[K any, V comparable](compare func(a, b K) int) *MultiSkipList[K, V] {
+	return &MultiSkipList[K, V]{
+		list: NewFunc[multiKey[K], V](func(a, b multiKey[K]) int {
+			if c := compare(a.key, b.key); c != 0 {
+				return c
+			}
+
+			return cmp.Compare(a.seq, b.seq)
+		}),
+	}
+}
+
+// Len 返回元素个数
+func (m *MultiSkipList[K, V]) Len() int {
+	return m.list.Len()
+}
+
+// Add 添加一个元素，已有相同 key 时排在它们之后
+func (m *MultiSkipList[K, V]) Add(key K, value V) {
+	m.seq++
+	m.list.Set(multiKey[K]{key: key, seq: m.seq}, value)
+}
+
+// first 返回 key 的第一个元素及其排名（从 1 开始），没有则返回 nil
+func (m *MultiSkipList[K, V]) first(key K) (*Element[multiKey[K], V], int) {
+	low := multiKey[K]{key: key, seq: multiSeqLow}
+	elem, rank := m.list.findFirstRank(func(elem *Element[multiKey[K], V]) bool {
+		return m.list.compareKey(low, elem.key) <= 0
+	})
+
+	if elem == nil || !m.equal(elem, key) {
+		return nil, rank
+	}
+
+	return elem, rank
+}
+
+// equal 返回 elem 的 key 是否等于 key，elem 必须不小于 key 的第一个元素
+func (m *MultiSkipList[K, V]) equal(elem *Element[multiKey[K], V], key K) bool {
+	return m.list.compareKey(multiKey[K]{key: key, seq: multiSeqHigh}, elem.key) >= 0
+}
+
+// Get 返回 key 最早插入的值
+func (m *MultiSkipList[K, V]) Get(key K) (value V, ok bool) {
+	if elem, _ := m.first(key); elem != nil {
+		return elem.Value, true
+	}
+
+	return
+}
+
+// GetAll 按插入顺序返回 key 的所有值，不存在时返回 nil
+func (m *MultiSkipList[K, V]) GetAll(key K) []V {
+	var values []V
+
+	for elem, _ := m.first(key); elem != nil && m.equal(elem, key); elem = elem.Next() {
+		values = append(values, elem.Value)
+	}
+
+	return values
+}
+
+// Count 返回 key 的元素个数，时间复杂度 O(log n)
+func (m *MultiSkipList[K, V]) Count(key K) int {
+	return m.list.Count(multiKey[K]{key: key, seq: multiSeqLow}, multiKey[K]{key: key, seq: multiSeqHigh})
+}
+
+// RemoveOne 删除 key 中最早插入的、值等于 value 的元素，返回是否删除
+func (m *MultiSkipList[K, V]) RemoveOne(key K, value V) bool {
+	for elem, _ := m.first(key); elem != nil && m.equal(elem, key); elem = elem.Next() {
+		if elem.Value == value {
+			m.list.RemoveElement(elem)
+			return true
+		}
+	}
+
+	return false
+}
+
+// RemoveAll 删除 key 的所有元素，返回删除的个数
+func (m *MultiSkipList[K, V]) RemoveAll(key K) int {
+	elem, rank := m.first(key)
+
+	if elem == nil {
+		return 0
+	}
+
+	start := rank - 1
+	return m.list.RemoveRangeByRank(start, start+m.Count(key)-1)
+}
+
+// RemoveFront 删除并返回第一个元素
+func (m *MultiSkipList[K, V]) RemoveFront() (key K, value V, ok bool) {
+	if elem := m.list.RemoveFront(); elem != nil {
+		return elem.key.key, elem.Value, true
+	}
+
+	return
+}
+
+// All 按 key 从小到大遍历所有元素，key 相同时按插入顺序
+func (m *MultiSkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, v := range m.list.All() {
+			if !yield(k.key, v) {
+				return
+			}
+		}
+	}
+}
+
+// Range 按 key 从小到大遍历 [from, to] 内的元素，flags 可以排除端点
+func (m *MultiSkipList[K, V]) Range(from, to K, flags RangeFlag) iter.Seq2[K, V] {
+	low := multiKey[K]{key: from, seq: multiSeqLow}
+	if flags&ExcludeFrom != 0 {
+		low.seq = multiSeqHigh
+	}
+
+	high := multiKey[K]{key: to, seq: multiSeqHigh}
+	if flags&ExcludeTo != 0 {
+		high.seq = multiSeqLow
+	}
+
+	return func(yield func(K, V) bool) {
+		for k, v := range m.list.Range(low, high, 0) {
+			if !yield(k.key, v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package skipList
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMulti_Basic(t *testing.T) {
+	a := assert.New(t)
+	m := NewMulti[int, string]()
+
+	m.Add(2, "b1")
+	m.Add(1, "a1")
+	m.Add(2, "b2")
+	m.Add(3, "c1")
+	m.Add(2, "b3")
+	a.Equal(5, m.Len())
+
+	a.Equal([]string{"b1", "b2", "b3"}, m.GetAll(2))
+	a.Nil(m.GetAll(4))
+	a.Equal(3, m.Count(2))
+	a.Equal(0, m.Count(4))
+
+	val, ok := m.Get(2)
+	a.True(ok)
+	a.Equal("b1", val)
+
+	a.True(m.RemoveOne(2, "b2"))
+	a.False(m.RemoveOne(2, "b2"))
+	a.Equal([]string{"b1", "b3"}, m.GetAll(2))
+
+	m.Add(2, "b1")
+	a.True(m.RemoveOne(2, "b1"))
+	a.Equal([]string{"b3", "b1"}, m.GetAll(2))
+
+	a.Equal([]int{1, 2, 2, 3}, collectKeys(m.All()))
+	a.Equal([]int{2, 2}, collectKeys(m.Range(1, 3, ExcludeFrom|ExcludeTo)))
+	a.Equal([]int{1, 2, 2}, collectKeys(m.Range(0, 2, 0)))
+
+	a.Equal(2, m.RemoveAll(2))
+	a.Equal(0, m.RemoveAll(2))
+	a.Equal(2, m.Len())
+
+	key, val, ok := m.RemoveFront()
+	a.True(ok)
+	a.Equal(1, key)
+	a.Equal("a1", val)
+	checkSpans(t, m.list)
+}
+
+func TestMulti_Random(t *testing.T) {
+	a := assert.New(t)
+	r := rand.New(rand.NewSource(1))
+	m := NewMulti[int, int]()
+	model := map[int][]int{}
+
+	for i := 0; i < 5000; i++ {
+		key := r.Intn(32)
+
+		switch r.Intn(5) {
+		case 0, 1:
+			m.Add(key, i)
+			model[key] = append(model[key], i)
+		case 2:
+			if len(model[key]) == 0 {
+				a.False(m.RemoveOne(key, 0))
+				continue
+			}
+			j := r.Intn(len(model[key]))
+			a.True(m.RemoveOne(key, model[key][j]))
+			model[key] = append(model[key][:j:j], model[key][j+1:]...)
+		case 3:
+			if r.Intn(4) == 0 {
+				a.Equal(len(model[key]), m.RemoveAll(key))
+				delete(model, key)
+			}
+		default:
+			if len(model[key]) == 0 {
+				a.Nil(m.GetAll(key))
+			} else {
+				a.Equal(model[key], m.GetAll(key))
+			}
+			a.Equal(len(model[key]), m.Count(key))
+		}
+	}
+
+	total := 0
+	for _, values := range model {
+		total += len(values)
+	}
+	a.Equal(total, m.Len())
+	checkSpans(t, m.list)
+}
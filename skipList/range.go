@@ -0,0 +1,142 @@
+package skipList
+
+import "iter"
+
+// RangeFlag Range 和 RangeReverse 的选项，默认包含两个端点
+type RangeFlag uint8
+
+const (
+	ExcludeFrom RangeFlag = 1 << iota // 不包含 from
+	ExcludeTo                         // 不包含 to
+)
+
+// Ceiling 返回大于或等于 key 的第一个元素。
+// 如果没有此类元素，则返回 nil。
+func (list *SkipList[K, V]) Ceiling(key K) *Element[K, V] {
+	score := list.calcScore(key)
+	return list.findFirst(func(elem *Element[K, V]) bool {
+		return list.compare(score, key, elem) <= 0
+	})
+}
+
+// Higher 返回大于 key 的第一个元素。
+// 如果没有此类元素，则返回 nil。
+func (list *SkipList[K, V]) Higher(key K) *Element[K, V] {
+	score := list.calcScore(key)
+	return list.findFirst(func(elem *Element[K, V]) bool {
+		return list.compare(score, key, elem) < 0
+	})
+}
+
+// Floor 返回小于或等于 key 的最后一个元素。
+// 如果没有此类元素，则返回 nil。
+func (list *SkipList[K, V]) Floor(key K) *Element[K, V] {
+	score := list.calcScore(key)
+	return list.findLast(func(elem *Element[K, V]) bool {
+		return list.compare(score, key, elem) >= 0
+	})
+}
+
+// Lower 返回小于 key 的最后一个元素。
+// 如果没有此类元素，则返回 nil。
+func (list *SkipList[K, V]) Lower(key K) *Element[K, V] {
+	score := list.calcScore(key)
+	return list.findLast(func(elem *Element[K, V]) bool {
+		return list.compare(score, key, elem) > 0
+	})
+}
+
+// FindPrev 返回 start 前小于或等于 key 的最后一个元素，与 FindNext 对应。
+// 如果 start 小于或等于 key，则返回 start。
+// 如果没有此类元素，则返回 nil。
+// 如果 start 为 nil，则从后面查找元素。
+func (list *SkipList[K, V]) FindPrev(start *Element[K, V], key K) *Element[K, V] {
+	if start != nil && list.compare(list.calcScore(key), key, start) >= 0 {
+		return start
+	}
+
+	return list.Floor(key)
+}
+
+// seq 从 start() 返回的元素开始沿 next 方向遍历，直到元素不满足 in。
+// 起点在开始遍历时才确定；调用 yield 前已经取得下一个元素，因此可以在遍历时删除当前元素。
+func (list *SkipList[K, V]) seq(start func() *Element[K, V], next func(*Element[K, V]) *Element[K, V], in func(*Element[K, V]) bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for elem := start(); elem != nil && (in == nil || in(elem)); {
+			n := next(elem)
+
+			if !yield(elem.key, elem.Value) {
+				return
+			}
+
+			elem = n
+		}
+	}
+}
+
+// All 按 key 从小到大遍历所有元素。
+func (list *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return list.seq(list.Front, (*Element[K, V]).Next, nil)
+}
+
+// Backward 按 key 从大到小遍历所有元素。
+func (list *SkipList[K, V]) Backward() iter.Seq2[K, V] {
+	return list.seq(list.Back, (*Element[K, V]).Prev, nil)
+}
+
+// Ascend 从大于或等于 from 的第一个元素开始，按 key 从小到大遍历。
+func (list *SkipList[K, V]) Ascend(from K) iter.Seq2[K, V] {
+	return list.seq(func() *Element[K, V] {
+		return list.Ceiling(from)
+	}, (*Element[K, V]).Next, nil)
+}
+
+// Range 按 key 从小到大遍历 [from, to] 内的元素，flags 可以排除端点。
+func (list *SkipList[K, V]) Range(from, to K, flags RangeFlag) iter.Seq2[K, V] {
+	toScore := list.calcScore(to)
+
+	return list.seq(func() *Element[K, V] {
+		if flags&ExcludeFrom != 0 {
+			return list.Higher(from)
+		}
+
+		return list.Ceiling(from)
+	}, (*Element[K, V]).Next, func(elem *Element[K, V]) bool {
+		if flags&ExcludeTo != 0 {
+			return list.compare(toScore, to, elem) > 0
+		}
+
+		return list.compare(toScore, to, elem) >= 0
+	})
+}
+
+// RangeReverse 按 key 从大到小遍历 [to, from] 内的元素，from 为较大的一端，flags 可以排除端点。
+func (list *SkipList[K, V]) RangeReverse(from, to K, flags RangeFlag) iter.Seq2[K, V] {
+	toScore := list.calcScore(to)
+
+	return list.seq(func() *Element[K, V] {
+		if flags&ExcludeFrom != 0 {
+			return list.Lower(from)
+		}
+
+		return list.Floor(from)
+	}, (*Element[K, V]).Prev, func(elem *Element[K, V]) bool {
+		if flags&ExcludeTo != 0 {
+			return list.compare(toScore, to, elem) < 0
+		}
+
+		return list.compare(toScore, to, elem) <= 0
+	})
+}
+
+// RangeByScore 按 key 从小到大遍历分数在 [min, max] 内的元素。
+// 只有通过 Comparable 创建的 skip list 才有分数，其他 skip list 中分数总是 0。
+func (list *SkipList[K, V]) RangeByScore(min, max float64) iter.Seq2[K, V] {
+	return list.seq(func() *Element[K, V] {
+		return list.findFirst(func(elem *Element[K, V]) bool {
+			return elem.score >= min
+		})
+	}, (*Element[K, V]).Next, func(elem *Element[K, V]) bool {
+		return elem.score <= max
+	})
+}
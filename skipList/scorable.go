@@ -8,6 +8,7 @@ import "reflect"
  * 时间 2024/9/7 22:00
  */
 
+// Scorable 自带分数的 key，可以通过 ByScore 作为 SkipList 的 key
 type Scorable interface {
 	Score() float64
 }
@@ -1,6 +1,8 @@
 package skipList
 
 import (
+	"cmp"
+	"errors"
 	"math/rand"
 	"time"
 )
@@ -14,44 +16,145 @@ var DefaultMaxLevel = 48
 const preallocDefaultMaxLevel = 48
 
 // SkipList is the header of a skip list.
-type SkipList struct {
-	elementHeader
+// K 为 key 的类型，V 为 value 的类型，元素按 key 从小到大排列。
+type SkipList[K, V any] struct {
+	elementHeader[K, V]
 
-	comparable Comparable
-	rand       *rand.Rand
+	compareKey func(a, b K) int
+	// scoreOf 通过 Comparable 创建时为其 CalcScore，分数不同的 key 不再调用 compareKey
+	scoreOf func(key K) float64
+	rand    *rand.Rand
+	// stopBelow rand.Int31() 小于 stopBelow 时停止提升层数，即不提升的概率为 stopBelow / 2^31
+	stopBelow int32
 
 	maxLevel int
 	length   int
-	back     *Element
+	back     *Element[K, V]
+
+	// recycle 为 true 时被删除的元素按大小等级放入 free，见 SetRecycle
+	recycle bool
+	free    [len(elementSizeClasses)][]*Element[K, V]
+
+	// clock 为 nil 时使用 SystemClock，见 SetClock
+	clock Clock
+}
+
+// New 创建一个 key 按自然顺序排列的 skip list。
+// 如果失败，返回 nil。
+func New[K cmp.Ordered, V any]() *SkipList[K, V] {
+	return NewFunc[K, V](cmp.Compare[K])
 }
 
-// New 将创建一个新的 skip list，其中包含 comparable to compare 键。
-// 有很多预定义的严格类型键，如 Int、Float64、String 等。
-// 我们可以通过实现 Comparable 接口来创建自定义 Comparitation。
+// NewFunc 创建一个使用 compare 比较 key 的 skip list。
+// compare 返回负数、0、正数分别表示 a 小于、等于、大于 b。
 // 如果失败，返回 nil。
-func New(comparable Comparable) *SkipList {
+func NewFunc[K, V any](compare func(a, b K) int) *SkipList[K, V] {
+	return newSkipList[K, V](compare, nil)
+}
+
+// NewComparable 创建一个使用 comparable 比较 key 的 skip list。
+// 元素先按 CalcScore 的分数排序，分数相同时再调用 Compare。
+// 有很多预定义的严格类型键，如 Int、Float64、String 等，也可以用 Reverse、Composite 等组合。
+// 如果失败，返回 nil。
+func NewComparable[K, V any](comparable Comparable[K]) *SkipList[K, V] {
+	return newSkipList[K, V](comparable.Compare, comparable.CalcScore)
+}
+
+func newSkipList[K, V any](compare func(a, b K) int, scoreOf func(key K) float64) *SkipList[K, V] {
 	if DefaultMaxLevel <= 0 {
 		return nil
 	}
 
 	source := rand.NewSource(time.Now().UnixNano())
-	return &SkipList{
-		elementHeader: elementHeader{
-			levels: make([]*Element, DefaultMaxLevel),
+	return &SkipList[K, V]{
+		elementHeader: elementHeader[K, V]{
+			levels: make([]*Element[K, V], DefaultMaxLevel),
+			spans:  make([]int, DefaultMaxLevel),
 		},
 
-		comparable: comparable,
+		compareKey: compare,
+		scoreOf:    scoreOf,
 		rand:       rand.New(source),
+		stopBelow:  defaultStopBelow,
 
 		maxLevel: DefaultMaxLevel,
 	}
 }
 
+// emptyCopy 返回一个与 list 使用相同顺序和配置的空列表，层数与 list 相同
+func (list *SkipList[K, V]) emptyCopy() *SkipList[K, V] {
+	return &SkipList[K, V]{
+		elementHeader: elementHeader[K, V]{
+			levels: make([]*Element[K, V], len(list.levels)),
+			spans:  make([]int, len(list.levels)),
+		},
+
+		compareKey: list.compareKey,
+		scoreOf:    list.scoreOf,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		stopBelow:  list.stopBelow,
+		recycle:    list.recycle,
+		clock:      list.clock,
+
+		maxLevel: list.maxLevel,
+	}
+}
+
+// ErrProbability 提升层数的概率不在 (0, 1) 内
+var ErrProbability = errors.New("skiplist: 概率必须在 (0, 1) 内")
+
+// defaultStopBelow 默认以 1/2 的概率提升层数
+const defaultStopBelow = 1 << 30
+
+// Options NewWithOptions 的配置
+type Options struct {
+	// Seed 随机数种子，相同的种子和相同的操作序列得到相同的结构，便于复现问题
+	Seed int64
+	// Probability 元素提升到上一层的概率，取值范围为 (0, 1)，为 0 时使用默认值 1/2
+	Probability float64
+	// MaxLevel 最大层数，为 0 时使用 DefaultMaxLevel
+	MaxLevel int
+}
+
+// NewWithOptions 使用 opts 创建一个 key 按自然顺序排列的 skip list。
+// 与 New 不同，随机数种子总是 opts.Seed，因此结构是确定的。
+// 如果 opts 无效，返回 nil。
+func NewWithOptions[K cmp.Ordered, V any](opts Options) *SkipList[K, V] {
+	list := New[K, V]()
+
+	if list == nil || opts.MaxLevel < 0 {
+		return nil
+	}
+
+	if opts.Probability != 0 && list.SetProbability(opts.Probability) != nil {
+		return nil
+	}
+
+	if opts.MaxLevel > 0 {
+		list.SetMaxLevel(opts.MaxLevel)
+	}
+
+	list.SetRandSource(rand.NewSource(opts.Seed))
+	return list
+}
+
+// SetProbability 设置元素提升到上一层的概率，只影响之后加入的元素。
+// p 必须在 (0, 1) 内，否则返回 ErrProbability。
+func (list *SkipList[K, V]) SetProbability(p float64) error {
+	if !(p > 0 && p < 1) {
+		return ErrProbability
+	}
+
+	list.stopBelow = int32((1 - p) * (1 << 31))
+	return nil
+}
+
 // Init 重置列表,并删除所有元素。
-func (list *SkipList) Init() *SkipList {
+func (list *SkipList[K, V]) Init() *SkipList[K, V] {
 	list.back = nil
 	list.length = 0
-	list.levels = make([]*Element, len(list.levels))
+	list.levels = make([]*Element[K, V], len(list.levels))
+	list.spans = make([]int, len(list.levels))
 	return list
 }
 
@@ -59,27 +162,28 @@ func (list *SkipList) Init() *SkipList {
 // 默认情况下，Skiplist 使用 math/rand 中定义的全局 rand。
 // 默认 rand 在生成任何数字之前获取全局互斥锁。
 // 如果 skiplist 受到 caller 的良好保护，则没有必要设置。
-func (list *SkipList) SetRandSource(source rand.Source) {
+func (list *SkipList[K, V]) SetRandSource(source rand.Source) {
 	list.rand = rand.New(source)
 }
 
 // Front 返回第一个元素。
-func (list *SkipList) Front() (front *Element) {
+func (list *SkipList[K, V]) Front() (front *Element[K, V]) {
 	return list.levels[0]
 }
 
 // Back 返回最后一个元素。
-func (list *SkipList) Back() *Element {
+func (list *SkipList[K, V]) Back() *Element[K, V] {
 	return list.back
 }
 
 // Len 返回列表的长度。
-func (list *SkipList) Len() int {
+func (list *SkipList[K, V]) Len() int {
 	return list.length
 }
 
 // Set 设置 键为 key 的元素为 value。
-func (list *SkipList) Set(key, value interface{}) (elem *Element) {
+// key 已存在时更新 value，并清除其过期时间。
+func (list *SkipList[K, V]) Set(key K, value V) (elem *Element[K, V]) {
 	score := list.calcScore(key)
 
 	// 预期列表为空
@@ -89,6 +193,7 @@ func (list *SkipList) Set(key, value interface{}) (elem *Element) {
 
 		for i := 0; i < level; i++ {
 			list.levels[i] = elem
+			list.spans[i] = 1
 		}
 
 		list.back = elem
@@ -100,17 +205,24 @@ func (list *SkipList) Set(key, value interface{}) (elem *Element) {
 	max := len(list.levels)
 	prevHeader := &list.elementHeader
 
-	var maxStaticAllocElemHeaders [preallocDefaultMaxLevel]*elementHeader
-	var prevElemHeaders []*elementHeader
+	var maxStaticAllocElemHeaders [preallocDefaultMaxLevel]*elementHeader[K, V]
+	var maxStaticAllocRanks [preallocDefaultMaxLevel]int
+	var prevElemHeaders []*elementHeader[K, V]
+	var prevRanks []int // prevElemHeaders 中各元素的排名，头节点为 0
 
 	if max <= preallocDefaultMaxLevel {
 		prevElemHeaders = maxStaticAllocElemHeaders[:max]
+		prevRanks = maxStaticAllocRanks[:max]
 	} else {
-		prevElemHeaders = make([]*elementHeader, max)
+		prevElemHeaders = make([]*elementHeader[K, V], max)
+		prevRanks = make([]int, max)
 	}
 
+	rank := 0
+
 	for i := max - 1; i >= 0; {
 		prevElemHeaders[i] = prevHeader
+		prevRanks[i] = rank
 
 		for next := prevHeader.levels[i]; next != nil; next = prevHeader.levels[i] {
 			if comp := list.compare(score, key, next); comp <= 0 {
@@ -119,20 +231,24 @@ func (list *SkipList) Set(key, value interface{}) (elem *Element) {
 				if comp == 0 {
 					elem = next
 					elem.Value = value
+					elem.expireAt = 0
 					return
 				}
 
 				break
 			}
 
+			rank += prevHeader.spans[i]
 			prevHeader = &next.elementHeader
 			prevElemHeaders[i] = prevHeader
+			prevRanks[i] = rank
 		}
 
 		//如果它们指向与 topLevel 相同的元素，则跳过级别。
 		topLevel := prevHeader.levels[i]
 		for i--; i >= 0 && prevHeader.levels[i] == topLevel; i-- {
 			prevElemHeaders[i] = prevHeader
+			prevRanks[i] = rank
 		}
 	}
 
@@ -150,10 +266,27 @@ func (list *SkipList) Set(key, value interface{}) (elem *Element) {
 		elem.prevTopLevel = prev.Element()
 	}
 
-	// 设置级别
+	// 设置级别，并根据前置元素的排名拆分跨度
+	// 新元素的排名为 prevRanks[0] + 1
+	elemRank := prevRanks[0] + 1
+
 	for i := 0; i < level; i++ {
-		elem.levels[i] = prevElemHeaders[i].levels[i]
-		prevElemHeaders[i].levels[i] = elem
+		prev := prevElemHeaders[i]
+
+		if prev.levels[i] != nil {
+			elem.spans[i] = prevRanks[i] + prev.spans[i] + 1 - elemRank
+		}
+
+		elem.levels[i] = prev.levels[i]
+		prev.levels[i] = elem
+		prev.spans[i] = elemRank - prevRanks[i]
+	}
+
+	// 更高的层跨过了新元素，跨度加一
+	for i := level; i < max; i++ {
+		if prev := prevElemHeaders[i]; prev.levels[i] != nil {
+			prev.spans[i]++
+		}
 	}
 
 	//找出带有 next 元素的最大级别。
@@ -191,7 +324,7 @@ func (list *SkipList) Set(key, value interface{}) (elem *Element) {
 	return
 }
 
-func (list *SkipList) findNext(start *Element, score float64, key interface{}) (elem *Element) {
+func (list *SkipList[K, V]) findNext(start *Element[K, V], score float64, key K) (elem *Element[K, V]) {
 	if list.length == 0 {
 		return
 	}
@@ -208,7 +341,7 @@ func (list *SkipList) findNext(start *Element, score float64, key interface{}) (
 		return
 	}
 
-	var prevHeader *elementHeader
+	var prevHeader *elementHeader[K, V]
 	if start == nil {
 		prevHeader = &list.elementHeader
 	} else {
@@ -239,23 +372,65 @@ func (list *SkipList) findNext(start *Element, score float64, key interface{}) (
 	return
 }
 
+// findFirstRank 返回第一个满足 pred 的元素及其排名（从 1 开始），没有则返回 nil 和 Len()+1。
+// pred 必须是单调的：某个元素满足之后，其后的所有元素都满足。
+func (list *SkipList[K, V]) findFirstRank(pred func(elem *Element[K, V]) bool) (*Element[K, V], int) {
+	prevHeader := &list.elementHeader
+	rank := 0
+
+	for i := len(prevHeader.levels) - 1; i >= 0; i-- {
+		for next := prevHeader.levels[i]; next != nil && !pred(next); next = prevHeader.levels[i] {
+			rank += prevHeader.spans[i]
+			prevHeader = &next.elementHeader
+		}
+	}
+
+	return prevHeader.levels[0], rank + 1
+}
+
+// findFirst 返回第一个满足 pred 的元素，没有则返回 nil。
+func (list *SkipList[K, V]) findFirst(pred func(elem *Element[K, V]) bool) *Element[K, V] {
+	elem, _ := list.findFirstRank(pred)
+	return elem
+}
+
+// findLastRank 返回最后一个满足 pred 的元素及其排名（从 1 开始），没有则返回 nil 和 0。
+// pred 必须是单调的：某个元素不满足之后，其后的所有元素都不满足。
+func (list *SkipList[K, V]) findLastRank(pred func(elem *Element[K, V]) bool) (*Element[K, V], int) {
+	elem, rank := list.findFirstRank(func(elem *Element[K, V]) bool {
+		return !pred(elem)
+	})
+
+	if elem == nil {
+		return list.back, list.length
+	}
+
+	return elem.prev, rank - 1
+}
+
+// findLast 返回最后一个满足 pred 的元素，没有则返回 nil。
+func (list *SkipList[K, V]) findLast(pred func(elem *Element[K, V]) bool) *Element[K, V] {
+	elem, _ := list.findLastRank(pred)
+	return elem
+}
+
 // FindNext 返回 start 后大于或等于 key 的第一个元素。
 // 如果 start 大于或等于 key，则返回 start。
 // 如果没有此类元素，则返回 nil。
 // 如果 start 为 nil，则从前面查找元素。
-func (list *SkipList) FindNext(start *Element, key interface{}) (elem *Element) {
+func (list *SkipList[K, V]) FindNext(start *Element[K, V], key K) (elem *Element[K, V]) {
 	return list.findNext(start, list.calcScore(key), key)
 }
 
 // Find 返回大于或等于 key 的第一个元素。
 // 它是 FindNext(nil,key) 的简写。
-func (list *SkipList) Find(key interface{}) (elem *Element) {
+func (list *SkipList[K, V]) Find(key K) (elem *Element[K, V]) {
 	return list.FindNext(nil, key)
 }
 
 // Get 返回一个带有 key 的元素。
 // 如果未找到 key，则返回 nil。
-func (list *SkipList) Get(key interface{}) (elem *Element) {
+func (list *SkipList[K, V]) Get(key K) (elem *Element[K, V]) {
 	score := list.calcScore(key)
 
 	firstElem := list.findNext(nil, score, key)
@@ -272,7 +447,7 @@ func (list *SkipList) Get(key interface{}) (elem *Element) {
 }
 
 // GetValue 返回具有键的元素的值。
-func (list *SkipList) GetValue(key interface{}) (val interface{}, ok bool) {
+func (list *SkipList[K, V]) GetValue(key K) (val V, ok bool) {
 	element := list.Get(key)
 
 	if element == nil {
@@ -286,11 +461,11 @@ func (list *SkipList) GetValue(key interface{}) (val interface{}, ok bool) {
 
 // MustGetValue 返回 key 对应的元素
 // 如果list 中不存在 则return nil
-func (list *SkipList) MustGetValue(key interface{}) interface{} {
+func (list *SkipList[K, V]) MustGetValue(key K) (val V) {
 	element := list.Get(key)
 
 	if element == nil {
-		return nil
+		return
 	}
 
 	return element.Value
@@ -298,7 +473,7 @@ func (list *SkipList) MustGetValue(key interface{}) interface{} {
 
 // Remove 删除元素。
 // 如果找到，则返回已删除的元素指针，如果未找到，则返回 nil。
-func (list *SkipList) Remove(key interface{}) (elem *Element) {
+func (list *SkipList[K, V]) Remove(key K) (elem *Element[K, V]) {
 	elem = list.Get(key)
 
 	if elem == nil {
@@ -310,7 +485,7 @@ func (list *SkipList) Remove(key interface{}) (elem *Element) {
 }
 
 // RemoveFront 删除 front element 节点并返回已删除的元素。
-func (list *SkipList) RemoveFront() (front *Element) {
+func (list *SkipList[K, V]) RemoveFront() (front *Element[K, V]) {
 	if list.length == 0 {
 		return
 	}
@@ -321,7 +496,7 @@ func (list *SkipList) RemoveFront() (front *Element) {
 }
 
 // RemoveBack 删除后面的元素节点并返回被删除的元素。
-func (list *SkipList) RemoveBack() (back *Element) {
+func (list *SkipList[K, V]) RemoveBack() (back *Element[K, V]) {
 	if list.length == 0 {
 		return
 	}
@@ -332,34 +507,63 @@ func (list *SkipList) RemoveBack() (back *Element) {
 }
 
 // RemoveElement 从列表中删除 elem。
-func (list *SkipList) RemoveElement(elem *Element) {
+func (list *SkipList[K, V]) RemoveElement(elem *Element[K, V]) {
 	if elem == nil || elem.list != list {
 		return
 	}
 
-	level := elem.Level()
+	max := len(list.levels)
 
+	var maxStaticAllocElemHeaders [preallocDefaultMaxLevel]*elementHeader[K, V]
+	var prevElemHeaders []*elementHeader[K, V]
+
+	if max <= preallocDefaultMaxLevel {
+		prevElemHeaders = maxStaticAllocElemHeaders[:max]
+	} else {
+		prevElemHeaders = make([]*elementHeader[K, V], max)
+	}
+
+	list.prevHeaders(elem, prevElemHeaders)
+	list.removeElement(elem, prevElemHeaders)
+}
+
+// prevHeaders 沿 prev 和 prevTopLevel 找出 elem 在每一层的前置元素
+// 某一层没有前置元素时为头节点。
+func (list *SkipList[K, V]) prevHeaders(elem *Element[K, V], prevElemHeaders []*elementHeader[K, V]) {
 	max := 0
-	prevElems := make([]*Element, level)
 	prev := elem.prev
 
-	for prev != nil && max < level {
+	for prev != nil && max < len(prevElemHeaders) {
 		prevLevel := len(prev.levels)
 
-		for ; max < prevLevel && max < level; max++ {
-			prevElems[max] = prev
+		for ; max < prevLevel && max < len(prevElemHeaders); max++ {
+			prevElemHeaders[max] = &prev.elementHeader
 		}
 
 		for prev = prev.prevTopLevel; prev != nil && prev.Level() == prevLevel; prev = prev.prevTopLevel {
 		}
 	}
 
-	for i := 0; i < max; i++ {
-		prevElems[i].levels[i] = elem.levels[i]
+	for ; max < len(prevElemHeaders); max++ {
+		prevElemHeaders[max] = &list.elementHeader
 	}
+}
 
-	for i := max; i < level; i++ {
-		list.levels[i] = elem.levels[i]
+// removeElement 删除 elem，prevElemHeaders 为 elem 在每一层的前置元素。
+// 删除之后 prevElemHeaders 仍然是 elem 后一个元素的前置元素，可以继续用于删除后续元素。
+func (list *SkipList[K, V]) removeElement(elem *Element[K, V], prevElemHeaders []*elementHeader[K, V]) {
+	level := elem.Level()
+
+	for i, prev := range prevElemHeaders {
+		if i < level {
+			if elem.levels[i] != nil {
+				prev.spans[i] += elem.spans[i] - 1
+			}
+
+			prev.levels[i] = elem.levels[i]
+		} else if prev.levels[i] != nil {
+			prev.spans[i]--
+		}
 	}
 
 	if next := elem.Next(); next != nil {
@@ -374,7 +578,7 @@ func (list *SkipList) RemoveElement(elem *Element) {
 		}
 
 		i = next.Level()
-		next.prevTopLevel = prevElems[i-1]
+		next.prevTopLevel = list.headerElement(prevElemHeaders[i-1])
 	}
 
 	if list.back == elem {
@@ -382,17 +586,152 @@ func (list *SkipList) RemoveElement(elem *Element) {
 	}
 
 	list.length--
-	elem.reset()
+	list.release(elem)
+}
+
+// headerElement 返回节点头对应的元素，头节点返回 nil
+func (list *SkipList[K, V]) headerElement(header *elementHeader[K, V]) *Element[K, V] {
+	if header == &list.elementHeader {
+		return nil
+	}
+
+	return header.Element()
+}
+
+// GetByRank 返回排名为 rank 的元素，排名从 0 开始。
+// 如果 rank 越界，则返回 nil。
+func (list *SkipList[K, V]) GetByRank(rank int) *Element[K, V] {
+	if rank < 0 || rank >= list.length {
+		return nil
+	}
+
+	prevHeader := &list.elementHeader
+	traversed := 0
+
+	for i := len(prevHeader.levels) - 1; i >= 0; i-- {
+		for next := prevHeader.levels[i]; next != nil && traversed+prevHeader.spans[i] <= rank+1; next = prevHeader.levels[i] {
+			traversed += prevHeader.spans[i]
+			prevHeader = &next.elementHeader
+		}
+
+		if traversed == rank+1 {
+			return prevHeader.Element()
+		}
+	}
+
+	return nil
+}
+
+// Rank 返回 key 的排名，排名从 0 开始。
+// 如果未找到 key，则返回 -1 和 false。
+func (list *SkipList[K, V]) Rank(key K) (rank int, ok bool) {
+	score := list.calcScore(key)
+	elem, r := list.findFirstRank(func(elem *Element[K, V]) bool {
+		return list.compare(score, key, elem) <= 0
+	})
+
+	if elem == nil || list.compare(score, key, elem) != 0 {
+		return -1, false
+	}
+
+	return r - 1, true
+}
+
+// Count 返回 key 在 [min, max] 内的元素个数。
+func (list *SkipList[K, V]) Count(min, max K) int {
+	minScore := list.calcScore(min)
+	maxScore := list.calcScore(max)
+	_, first := list.findFirstRank(func(elem *Element[K, V]) bool {
+		return list.compare(minScore, min, elem) <= 0
+	})
+	_, last := list.findFirstRank(func(elem *Element[K, V]) bool {
+		return list.compare(maxScore, max, elem) < 0
+	})
+
+	if last < first {
+		return 0
+	}
+
+	return last - first
+}
+
+// RemoveRangeByRank 删除排名在 [start, stop] 内的元素，返回删除的个数。
+// 排名从 0 开始，可以为负数，-1 表示最后一个元素。
+func (list *SkipList[K, V]) RemoveRangeByRank(start, stop int) (removed int) {
+	start, stop, ok := normalizeRank(start, stop, list.length)
+
+	if !ok {
+		return
+	}
+
+	max := len(list.levels)
+
+	var maxStaticAllocElemHeaders [preallocDefaultMaxLevel]*elementHeader[K, V]
+	var prevElemHeaders []*elementHeader[K, V]
+
+	if max <= preallocDefaultMaxLevel {
+		prevElemHeaders = maxStaticAllocElemHeaders[:max]
+	} else {
+		prevElemHeaders = make([]*elementHeader[K, V], max)
+	}
+
+	// 找到排名为 start 的元素在每一层的前置元素
+	prevHeader := &list.elementHeader
+	traversed := 0
+
+	for i := max - 1; i >= 0; i-- {
+		for next := prevHeader.levels[i]; next != nil && traversed+prevHeader.spans[i] <= start; next = prevHeader.levels[i] {
+			traversed += prevHeader.spans[i]
+			prevHeader = &next.elementHeader
+		}
+
+		prevElemHeaders[i] = prevHeader
+	}
+
+	elem := prevHeader.levels[0]
+
+	for ; removed <= stop-start; removed++ {
+		next := elem.Next()
+		list.removeElement(elem, prevElemHeaders)
+		elem = next
+	}
+
+	return
+}
+
+// normalizeRank 按 Redis 的规则处理负数排名和越界，返回闭区间 [start, stop]
+func normalizeRank(start, stop, length int) (int, int, bool) {
+	if start < 0 {
+		start += length
+	}
+
+	if stop < 0 {
+		stop += length
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if stop >= length {
+		stop = length - 1
+	}
+
+	if start > stop || start >= length {
+		return 0, 0, false
+	}
+
+	return start, stop, true
 }
 
 // MaxLevel 返回当前 Max Level 值。
-func (list *SkipList) MaxLevel() int {
+func (list *SkipList[K, V]) MaxLevel() int {
 	return list.maxLevel
 }
 
 // SetMaxLevel 更改跳过列表最大级别。
 // 如果 level 不大于 0，则返回 -1。
-func (list *SkipList) SetMaxLevel(level int) (old int) {
+func (list *SkipList[K, V]) SetMaxLevel(level int) (old int) {
 	if level <= 0 {
 		return -1
 	}
@@ -405,36 +744,42 @@ func (list *SkipList) SetMaxLevel(level int) (old int) {
 	}
 
 	if old > level {
+		// 不能删除仍然有元素的层
 		for i := old - 1; i >= level; i-- {
 			if list.levels[i] != nil {
-				level = i
+				level = i + 1
 				break
 			}
 		}
 
 		list.levels = list.levels[:level]
+		list.spans = list.spans[:level]
 		return
 	}
 
 	if level <= cap(list.levels) {
 		list.levels = list.levels[:level]
+		list.spans = list.spans[:level]
 		return
 	}
 
-	levels := make([]*Element, level)
+	levels := make([]*Element[K, V], level)
 	copy(levels, list.levels)
 	list.levels = levels
+
+	spans := make([]int, level)
+	copy(spans, list.spans)
+	list.spans = spans
 	return
 }
 
-func (list *SkipList) randLevel() int {
+func (list *SkipList[K, V]) randLevel() int {
 	estimated := list.maxLevel
-	const prob = 1 << 30 // Half of 2^31.
 	rand := list.rand
 	i := 1
 
 	for ; i < estimated; i++ {
-		if rand.Int31() < prob {
+		if rand.Int31() < list.stopBelow {
 			break
 		}
 	}
@@ -443,8 +788,8 @@ func (list *SkipList) randLevel() int {
 }
 
 // compare 比较两个元素的值并返回 -1、0 和 1。
-func (list *SkipList) compare(score float64, key interface{}, rhs *Element) int {
-	if score != rhs.score {
+func (list *SkipList[K, V]) compare(score float64, key K, rhs *Element[K, V]) int {
+	if list.scoreOf != nil && score != rhs.score {
 		if score > rhs.score {
 			return 1
 		} else if score < rhs.score {
@@ -454,10 +799,13 @@ func (list *SkipList) compare(score float64, key interface{}, rhs *Element) int
 		return 0
 	}
 
-	return list.comparable.Compare(key, rhs.key)
+	return list.compareKey(key, rhs.key)
 }
 
-func (list *SkipList) calcScore(key interface{}) (score float64) {
-	score = list.comparable.CalcScore(key)
+func (list *SkipList[K, V]) calcScore(key K) (score float64) {
+	if list.scoreOf != nil {
+		score = list.scoreOf(key)
+	}
+
 	return
 }
@@ -1,8 +1,13 @@
 package skipList
 
 import (
-	"github.com/stretchr/testify/assert"
+	"fmt"
+	"iter"
+	"math/rand"
+	"sort"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 /*
@@ -12,7 +17,7 @@ import (
  */
 func TestBasicCRUD(t *testing.T) {
 	a := assert.New(t)
-	list := New(float64Type)
+	list := New[float64, string]()
 	a.True(list.Len() == 0)
 	//a.Equal(list.Find(0), nil)
 
@@ -25,7 +30,7 @@ func TestBasicCRUD(t *testing.T) {
 	//a.Equal(elem1.Prev(), nil)
 	a.Equal(list.Find(0), elem1)
 	a.Equal(list.Find(12.34), elem1)
-	a.Equal(list.Find(15), nil)
+	a.Nil(list.Find(15))
 
 	elem2 := list.Set(23.45, "second")
 	a.True(elem2 != nil)
@@ -121,22 +126,281 @@ func TestBasicCRUD(t *testing.T) {
 	a.Equal(list.Find(-99), elem4)
 	a.Equal(list.Find(10), elem1)
 	a.Equal(list.Find(15), elem3)
-	//a.Equal(list.Find(20), nil)
+	a.Nil(list.Find(20))
 
+	front := list.RemoveFront()
+	a.Equal(front, elem4)
 	a.Equal(list.Len(), 2)
 	a.Equal(list.Front(), elem1)
 	a.Equal(list.Back(), elem5)
 	a.Equal(list.Find(-99), elem1)
 
+	back := list.RemoveBack()
+	a.Equal(back, elem5)
 	a.Equal(list.Len(), 1)
 	a.Equal(list.Front(), elem1)
 	a.Equal(list.Back(), elem1)
-	a.Equal(list.Find(15), nil)
+	a.Nil(list.Find(15))
 	a.Equal(list.FindNext(nil, 10), elem1)
 	a.Equal(list.FindNext(elem1, 10), elem1)
-	a.Equal(list.FindNext(nil, 15), nil)
+	a.Nil(list.FindNext(nil, 15))
 
 	list.Init()
 	a.Equal(list.Len(), 0)
-	a.Equal(list.Get(12.34), nil)
+	a.Nil(list.Get(12.34))
+}
+
+// checkSpans 校验每一层的跨度与第 0 层的距离一致
+func checkSpans[K, V any](t *testing.T, list *SkipList[K, V]) {
+	t.Helper()
+	ranks := make(map[*Element[K, V]]int, list.Len())
+	rank := 0
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		rank++
+		ranks[elem] = rank
+	}
+	if rank != list.Len() {
+		t.Fatalf("第 0 层有 %d 个元素, Len() = %d", rank, list.Len())
+	}
+
+	check := func(header *elementHeader[K, V], from int) {
+		for i, next := range header.levels {
+			if next != nil && ranks[next]-from != header.spans[i] {
+				t.Fatalf("排名 %d 第 %d 层跨度为 %d, want %d", from, i, header.spans[i], ranks[next]-from)
+			}
+		}
+	}
+	check(&list.elementHeader, 0)
+	for elem, r := range ranks {
+		check(&elem.elementHeader, r)
+	}
+}
+
+func TestRank(t *testing.T) {
+	a := assert.New(t)
+	list := New[int, int]()
+	rnd := rand.New(rand.NewSource(1))
+	list.SetRandSource(rand.NewSource(2))
+	keys := make(map[int]bool)
+
+	for i := 0; i < 3000; i++ {
+		key := rnd.Intn(1000)
+		if rnd.Intn(3) == 0 {
+			list.Remove(key)
+			delete(keys, key)
+		} else {
+			list.Set(key, i)
+			keys[key] = true
+		}
+	}
+	checkSpans(t, list)
+
+	sorted := make([]int, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Ints(sorted)
+
+	for i, key := range sorted {
+		a.Equal(key, list.GetByRank(i).Key())
+		rank, ok := list.Rank(key)
+		a.True(ok)
+		a.Equal(i, rank)
+	}
+	a.Nil(list.GetByRank(-1))
+	a.Nil(list.GetByRank(len(sorted)))
+	_, ok := list.Rank(1000)
+	a.False(ok)
+
+	lo, hi := 200, 700
+	want := sort.SearchInts(sorted, hi+1) - sort.SearchInts(sorted, lo)
+	a.Equal(want, list.Count(lo, hi))
+	a.Equal(0, list.Count(hi, lo))
+
+	a.Equal(11, list.RemoveRangeByRank(10, 20))
+	sorted = append(sorted[:10], sorted[21:]...)
+	a.Equal(2, list.RemoveRangeByRank(-2, -1))
+	sorted = sorted[:len(sorted)-2]
+	a.Equal(0, list.RemoveRangeByRank(5, 4))
+	checkSpans(t, list)
+	a.Equal(len(sorted), list.Len())
+	for i, key := range sorted {
+		a.Equal(key, list.GetByRank(i).Key())
+	}
+	a.Equal(sorted[len(sorted)-1], list.Back().Key())
+
+	// 降低最大层数后仍能正确维护跨度
+	list.SetMaxLevel(4)
+	for i := 1000; i < 1100; i++ {
+		list.Set(i, i)
+	}
+	list.RemoveRangeByRank(0, 50)
+	checkSpans(t, list)
+}
+
+func TestComparable(t *testing.T) {
+	a := assert.New(t)
+	list := NewComparable[any, string](String)
+	list.Set("banana", "b")
+	list.Set("apple", "a")
+	list.Set("applesauce", "as")
+	list.Set("cherry", "c")
+
+	var keys []any
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Key())
+	}
+	a.Equal([]any{"apple", "applesauce", "banana", "cherry"}, keys)
+	a.Equal("as", list.MustGetValue("applesauce"))
+	a.Panics(func() {
+		list.Set(1, "wrong type")
+	})
+
+	byLen := NewFunc[string, int](func(a, b string) int {
+		return len(a) - len(b)
+	})
+	byLen.Set("ccc", 3)
+	byLen.Set("a", 1)
+	byLen.Set("bb", 2)
+	byLen.Set("dd", 4)
+	a.Equal(3, byLen.Len())
+	a.Equal(4, byLen.MustGetValue("xx"))
+	a.Equal(0.0, byLen.Front().Score())
+}
+
+const benchSize = 1 << 16
+
+func benchKeys() []int {
+	return rand.New(rand.NewSource(1)).Perm(benchSize)
+}
+
+// BenchmarkSet 对比泛型 key 与基于反射的 KeyType
+func BenchmarkSet(b *testing.B) {
+	keys := benchKeys()
+	b.Run("Generic", func(b *testing.B) {
+		b.ReportAllocs()
+		list := New[int, int]()
+		for i := 0; i < b.N; i++ {
+			key := keys[i%benchSize]
+			list.Set(key, key)
+		}
+	})
+	b.Run("Reflect", func(b *testing.B) {
+		b.ReportAllocs()
+		list := NewComparable[any, any](Int)
+		for i := 0; i < b.N; i++ {
+			key := keys[i%benchSize]
+			list.Set(key, key)
+		}
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	keys := benchKeys()
+	b.Run("Generic", func(b *testing.B) {
+		list := New[int, int]()
+		for _, key := range keys {
+			list.Set(key, key)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			list.Get(keys[i%benchSize])
+		}
+	})
+	b.Run("Reflect", func(b *testing.B) {
+		list := NewComparable[any, any](Int)
+		for _, key := range keys {
+			list.Set(key, key)
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			list.Get(keys[i%benchSize])
+		}
+	})
+}
+
+func collectKeys[K, V any](seq iter.Seq2[K, V]) []K {
+	var keys []K
+	for key := range seq {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func collectValues[K, V any](seq iter.Seq2[K, V]) []V {
+	var values []V
+	for _, value := range seq {
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestRange(t *testing.T) {
+	a := assert.New(t)
+	list := New[int, string]()
+	for i := 10; i >= 0; i -= 2 {
+		list.Set(i, fmt.Sprint(i))
+	}
+
+	a.Equal([]int{0, 2, 4, 6, 8, 10}, collectKeys(list.All()))
+	a.Equal([]int{10, 8, 6, 4, 2, 0}, collectKeys(list.Backward()))
+	a.Equal([]int{6, 8, 10}, collectKeys(list.Ascend(5)))
+	a.Equal([]int{2, 4, 6}, collectKeys(list.Range(2, 6, 0)))
+	a.Equal([]int{4}, collectKeys(list.Range(2, 6, ExcludeFrom|ExcludeTo)))
+	a.Equal([]int{2, 4}, collectKeys(list.Range(1, 6, ExcludeTo)))
+	a.Nil(collectKeys(list.Range(6, 2, 0)))
+	a.Equal([]int{6, 4, 2}, collectKeys(list.RangeReverse(6, 2, 0)))
+	a.Equal([]int{4}, collectKeys(list.RangeReverse(6, 2, ExcludeFrom|ExcludeTo)))
+	a.Equal([]int{6, 4}, collectKeys(list.RangeReverse(7, 3, 0)))
+
+	// 提前结束以及遍历时删除当前元素
+	var first []int
+	for key := range list.All() {
+		first = append(first, key)
+		if len(first) == 2 {
+			break
+		}
+	}
+	a.Equal([]int{0, 2}, first)
+	for key := range list.Range(4, 8, 0) {
+		list.Remove(key)
+	}
+	a.Equal([]int{0, 2, 10}, collectKeys(list.All()))
+
+	scored := NewComparable[any, int](Float64)
+	for _, score := range []float64{1.5, 2.5, 3.5, 4.5} {
+		scored.Set(score, 0)
+	}
+	a.Equal([]any{2.5, 3.5}, collectKeys(scored.RangeByScore(2, 4)))
+}
+
+func TestFindNeighbors(t *testing.T) {
+	a := assert.New(t)
+	list := New[int, int]()
+	for i := 0; i <= 10; i += 2 {
+		list.Set(i, i)
+	}
+	key := func(elem *Element[int, int]) any {
+		if elem == nil {
+			return nil
+		}
+		return elem.Key()
+	}
+
+	a.Equal(4, key(list.Ceiling(3)))
+	a.Equal(4, key(list.Ceiling(4)))
+	a.Equal(6, key(list.Higher(4)))
+	a.Equal(2, key(list.Floor(3)))
+	a.Equal(4, key(list.Floor(4)))
+	a.Equal(2, key(list.Lower(4)))
+	a.Nil(key(list.Higher(10)))
+	a.Nil(key(list.Lower(0)))
+	a.Nil(key(list.Floor(-1)))
+	a.Equal(10, key(list.Floor(100)))
+
+	a.Equal(6, key(list.FindPrev(nil, 7)))
+	a.Equal(4, key(list.FindPrev(list.Get(4), 7)))
+	a.Equal(2, key(list.FindPrev(list.Get(8), 3)))
 }
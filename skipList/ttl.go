@@ -0,0 +1,129 @@
+package skipList
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock 时间来源，测试时可以注入自定义的实现
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock 使用 time.Now 的时钟
+var SystemClock Clock = systemClock{}
+
+// SetClock 设置 SetWithTTL 和 reaper 使用的时钟，clock 为 nil 时使用 SystemClock
+func (list *SkipList[K, V]) SetClock(clock Clock) {
+	list.clock = clock
+}
+
+func (list *SkipList[K, V]) now() time.Time {
+	if list.clock == nil {
+		return SystemClock.Now()
+	}
+
+	return list.clock.Now()
+}
+
+// SetWithTTL 设置键为 key 的元素为 value，并在 ttl 之后过期，ttl 不大于 0 时等同于 Set。
+// 过期的元素不会自动隐藏，由 ExpireBefore 或 StartReaper 启动的 reaper 删除。
+func (list *SkipList[K, V]) SetWithTTL(key K, value V, ttl time.Duration) *Element[K, V] {
+	elem := list.Set(key, value)
+
+	if ttl > 0 {
+		elem.expireAt = list.now().Add(ttl).UnixNano()
+	}
+
+	return elem
+}
+
+// ExpireBefore 从第一个元素开始删除过期时间不晚于 t 的元素，返回删除的个数。
+// 遇到第一个未过期或没有过期时间的元素时停止，因此只删除过期的前缀，
+// 时间复杂度为 O(m)，m 为删除的个数。
+// 适用于过期时间随 key 递增的场景，例如以时间戳为 key 的滑动窗口；
+// 过期时间与 key 的顺序无关时，排在未过期元素之后的过期元素不会被删除。
+func (list *SkipList[K, V]) ExpireBefore(t time.Time) int {
+	deadline := t.UnixNano()
+	n := 0
+
+	for elem := list.Front(); elem != nil && elem.expireAt != 0 && elem.expireAt <= deadline; elem = elem.Next() {
+		n++
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	return list.RemoveRangeByRank(0, n-1)
+}
+
+// Reaper 定期删除 SkipList 中过期元素的后台任务
+type Reaper struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// StartReaper 启动一个后台 goroutine，每隔 interval 以时钟的当前时间调用一次 ExpireBefore。
+// 触发使用真实时间的 time.Ticker，需要由测试控制触发时机时使用 StartReaperOn。
+// SkipList 不是并发安全的，mu 必须是调用方保护该列表的锁，reaper 删除元素时持有它。
+// interval 不大于 0 时不启动，返回 nil。不再使用时需要调用 Reaper.Stop。
+func (list *SkipList[K, V]) StartReaper(interval time.Duration, mu sync.Locker) *Reaper {
+	if interval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	return list.startReaper(ticker.C, mu, ticker.Stop)
+}
+
+// StartReaperOn 与 StartReaper 相同，但每从 ticks 收到一个值时执行一次，
+// 删除的依据仍然是 SetClock 设置的时钟，而不是收到的值。
+// ticks 为无缓冲 channel 时，发送返回说明上一次删除已经完成，测试可以据此同步。
+func (list *SkipList[K, V]) StartReaperOn(ticks <-chan time.Time, mu sync.Locker) *Reaper {
+	return list.startReaper(ticks, mu, nil)
+}
+
+func (list *SkipList[K, V]) startReaper(ticks <-chan time.Time, mu sync.Locker, stopTicks func()) *Reaper {
+	r := &Reaper{stop: make(chan struct{}), done: make(chan struct{})}
+
+	go func() {
+		defer close(r.done)
+
+		if stopTicks != nil {
+			defer stopTicks()
+		}
+
+		for {
+			select {
+			case <-ticks:
+				mu.Lock()
+				list.ExpireBefore(list.now())
+				mu.Unlock()
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+
+	return r
+}
+
+// Stop 停止 reaper 并等待其退出，可以重复调用，r 为 nil 时什么也不做
+func (r *Reaper) Stop() {
+	if r == nil {
+		return
+	}
+
+	r.once.Do(func() {
+		close(r.stop)
+	})
+	<-r.done
+}
@@ -0,0 +1,148 @@
+package skipList
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestExpireBefore(t *testing.T) {
+	a := assert.New(t)
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	list := New[int64, int]()
+	list.SetClock(clock)
+
+	// 以时间戳为 key 的滑动窗口，每个元素存活 10 秒
+	for i := 0; i < 100; i++ {
+		list.SetWithTTL(clock.Now().Unix(), i, 10*time.Second)
+		clock.Advance(time.Second)
+	}
+
+	a.Equal(time.Unix(1010, 0), list.Front().ExpireAt())
+	a.Equal(0, list.ExpireBefore(time.Unix(1009, 0)))
+	a.Equal(1, list.ExpireBefore(time.Unix(1010, 0)))
+	a.Equal(90, list.ExpireBefore(clock.Now()))
+	a.Equal(9, list.Len())
+	a.Equal(int64(1091), list.Front().Key())
+	checkValid(t, list)
+
+	// 没有过期时间的元素挡住后面的过期元素
+	a.True(list.Set(1095, 0).ExpireAt().IsZero())
+	a.Equal(4, list.ExpireBefore(clock.Now().Add(time.Hour)))
+	a.Equal(int64(1095), list.Front().Key())
+	a.Equal(5, list.Len())
+
+	// ttl 不大于 0 时等同于 Set
+	a.True(list.SetWithTTL(1096, 0, 0).ExpireAt().IsZero())
+	a.Equal(0, list.ExpireBefore(clock.Now().Add(time.Hour)))
+
+	list.RemoveFront()
+	list.RemoveFront()
+	a.Equal(3, list.ExpireBefore(clock.Now().Add(time.Hour)))
+	a.Equal(0, list.Len())
+	checkValid(t, list)
+}
+
+func TestExpireBefore_Recycle(t *testing.T) {
+	a := assert.New(t)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	list := New[int, int]()
+	list.SetClock(clock)
+	list.SetRecycle(true)
+
+	for i := 0; i < 100; i++ {
+		list.SetWithTTL(i, i, time.Second)
+	}
+	clock.Advance(time.Second)
+	a.Equal(100, list.ExpireBefore(clock.Now()))
+
+	// 复用的元素不保留原来的过期时间
+	for i := 0; i < 100; i++ {
+		a.True(list.Set(i, i).ExpireAt().IsZero())
+	}
+	a.Equal(0, list.ExpireBefore(clock.Now().Add(time.Hour)))
+	checkValid(t, list)
+}
+
+func TestReaper(t *testing.T) {
+	a := assert.New(t)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	list := New[int, int]()
+	list.SetClock(clock)
+
+	var mu sync.Mutex
+	for i := 0; i < 10; i++ {
+		list.SetWithTTL(i, i, time.Duration(i+1)*time.Second)
+	}
+
+	ticks := make(chan time.Time)
+	reaper := list.StartReaperOn(ticks, &mu)
+	defer reaper.Stop()
+
+	// 第二次发送返回时，第一次触发的删除已经完成
+	clock.Advance(5 * time.Second)
+	ticks <- time.Time{}
+	ticks <- time.Time{}
+	mu.Lock()
+	a.Equal(5, list.Len())
+	mu.Unlock()
+
+	clock.Advance(2 * time.Second)
+	ticks <- time.Time{}
+	ticks <- time.Time{}
+	mu.Lock()
+	a.Equal(3, list.Len())
+	mu.Unlock()
+
+	// 停止后不再删除
+	reaper.Stop()
+	reaper.Stop()
+	clock.Advance(time.Hour)
+	select {
+	case ticks <- time.Time{}:
+		t.Fatal("Stop 之后 reaper 仍在接收")
+	default:
+	}
+	a.Equal(3, list.Len())
+}
+
+func TestStartReaper(t *testing.T) {
+	a := assert.New(t)
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	list := New[int, int]()
+	list.SetClock(clock)
+
+	var mu sync.Mutex
+	a.Nil(list.StartReaper(0, &mu))
+	(*Reaper)(nil).Stop()
+
+	list.SetWithTTL(1, 1, time.Second)
+	clock.Advance(time.Second)
+
+	reaper := list.StartReaper(time.Millisecond, &mu)
+	defer reaper.Stop()
+	a.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return list.Len() == 0
+	}, time.Second, time.Millisecond)
+}
@@ -2,8 +2,11 @@ package skipList
 
 import (
 	"bytes"
+	"cmp"
+	"encoding/binary"
 	"fmt"
 	"reflect"
+	"unsafe"
 )
 
 /*
@@ -12,31 +15,35 @@ import (
  * 时间 2024/9/2 8:49
  */
 
-// keyType 自定义跳表key值
-type keyType int
+// KeyType 预定义的 key 类型，key 为 interface{}，按 KeyType 对应的类型比较
+// 配合 NewComparable[any, V] 使用，例如 NewComparable[any, string](Int)。
+// 数字类型的分数为 key 的值；String 和 Bytes 没有分数，总是按字节序比较。
+// key 的类型与 KeyType 不一致时 CalcScore 会 panic，int 和 float64 可以用于任意数字类型。
+type KeyType int
 
 var typeOfBytes = reflect.TypeOf([]byte(nil))
 
-var _ Comparable = keyType(0)
+var _ Comparable[any] = KeyType(0)
 
+// 预定义的 key 类型
 const (
-	byteType    = keyType(reflect.Uint8)
-	runeType    = keyType(reflect.Int32)
-	intType     = keyType(reflect.Int)
-	int8Type    = keyType(reflect.Int8)
-	int16Type   = keyType(reflect.Int16)
-	int32Type   = keyType(reflect.Int32)
-	int64Type   = keyType(reflect.Int64)
-	uintType    = keyType(reflect.Uint)
-	uint8Type   = keyType(reflect.Uint8)
-	uint16Type  = keyType(reflect.Uint16)
-	uint32Type  = keyType(reflect.Uint32)
-	uint64Type  = keyType(reflect.Uint64)
-	uintptrType = keyType(reflect.Uintptr)
-	float32Type = keyType(reflect.Float32)
-	float64Type = keyType(reflect.Float64)
-	stringType  = keyType(reflect.String)
-	bytesType   = keyType(reflect.Slice)
+	Byte    = KeyType(reflect.Uint8)
+	Rune    = KeyType(reflect.Int32)
+	Int     = KeyType(reflect.Int)
+	Int8    = KeyType(reflect.Int8)
+	Int16   = KeyType(reflect.Int16)
+	Int32   = KeyType(reflect.Int32)
+	Int64   = KeyType(reflect.Int64)
+	Uint    = KeyType(reflect.Uint)
+	Uint8   = KeyType(reflect.Uint8)
+	Uint16  = KeyType(reflect.Uint16)
+	Uint32  = KeyType(reflect.Uint32)
+	Uint64  = KeyType(reflect.Uint64)
+	Uintptr = KeyType(reflect.Uintptr)
+	Float32 = KeyType(reflect.Float32)
+	Float64 = KeyType(reflect.Float64)
+	String  = KeyType(reflect.String)
+	Bytes   = KeyType(reflect.Slice)
 )
 
 var numberLikeKinds = [...]bool{
@@ -57,7 +64,24 @@ var numberLikeKinds = [...]bool{
 	reflect.Slice:   false,
 }
 
-func (kt keyType) Compare(a, b interface{}) int {
+func (kt KeyType) Compare(a, b interface{}) int {
+	// 常见的字节类 key 不经过反射
+	switch kt.kind() {
+	case reflect.String:
+		if s1, ok := a.(string); ok {
+			if s2, ok := b.(string); ok {
+				return CompareString(s1, s2)
+			}
+		}
+
+	case reflect.Slice:
+		if b1, ok := a.([]byte); ok {
+			if b2, ok := b.([]byte); ok {
+				return CompareBytes(b1, b2)
+			}
+		}
+	}
+
 	val1 := reflect.ValueOf(a)
 	val2 := reflect.ValueOf(b)
 	kind := kt.kind()
@@ -66,7 +90,20 @@ func (kt keyType) Compare(a, b interface{}) int {
 	return result
 }
 
-func (kt keyType) CalcScore(key interface{}) float64 {
+func (kt KeyType) CalcScore(key interface{}) float64 {
+	// 字节类的 key 没有分数，只需检查类型
+	switch key.(type) {
+	case string:
+		if kt == String {
+			return 0
+		}
+
+	case []byte:
+		if kt == Bytes {
+			return 0
+		}
+	}
+
 	k := reflect.ValueOf(key)
 	kind := kt.kind()
 
@@ -139,29 +176,56 @@ func compareTypes(lhs, rhs reflect.Value, kind reflect.Kind) int {
 		return 0
 
 	case reflect.String:
-		v1 := lhs.String()
-		v2 := rhs.String()
-
-		if v1 == v2 {
-			return 0
-		}
-
-		if v1 > v2 {
-			return 1
-		}
-
-		return -1
+		return CompareString(lhs.String(), rhs.String())
 
 	case reflect.Slice:
 		if lhs.Type().ConvertibleTo(typeOfBytes) && rhs.Type().ConvertibleTo(typeOfBytes) {
 			bytes1 := lhs.Convert(typeOfBytes).Interface().([]byte)
 			bytes2 := rhs.Convert(typeOfBytes).Interface().([]byte)
-			return bytes.Compare(bytes1, bytes2)
+			return CompareBytes(bytes1, bytes2)
 		}
 	}
 	return 0
 }
 
+// CompareBytes 按字节序比较 a 和 b，结果与 bytes.Compare 相同。
+// 先把前 8 个字节按大端序读成 uint64 比较，前缀相同时再逐字节比较剩余部分，
+// 不经过 float64，因此不会丢失精度。
+func CompareBytes(a, b []byte) int {
+	p1, p2 := prefixUint64(a), prefixUint64(b)
+
+	if p1 != p2 {
+		if p1 < p2 {
+			return -1
+		}
+
+		return 1
+	}
+
+	// 前缀相同且有一方不超过 8 个字节时，较短的一方是另一方的前缀
+	if len(a) <= 8 || len(b) <= 8 {
+		return cmp.Compare(len(a), len(b))
+	}
+
+	return bytes.Compare(a[8:], b[8:])
+}
+
+// CompareString 按字节序比较 a 和 b，结果与 strings.Compare 相同，算法与 CompareBytes 相同。
+func CompareString(a, b string) int {
+	return CompareBytes(unsafe.Slice(unsafe.StringData(a), len(a)), unsafe.Slice(unsafe.StringData(b), len(b)))
+}
+
+// prefixUint64 把前 8 个字节按大端序读成 uint64，不足 8 个字节时在末尾补 0
+func prefixUint64(data []byte) uint64 {
+	if len(data) >= 8 {
+		return binary.BigEndian.Uint64(data)
+	}
+
+	var buf [8]byte
+	copy(buf[:], data)
+	return binary.BigEndian.Uint64(buf[:])
+}
+
 // calcScore 计算key的分数
 func calcScore(val reflect.Value) (score float64) {
 	switch val.Kind() {
@@ -174,52 +238,15 @@ func calcScore(val reflect.Value) (score float64) {
 	case reflect.Float32, reflect.Float64:
 		score = val.Float()
 
-	case reflect.String:
-		// 将字符串进行哈希计算
-		var hash uint64
-		str := val.String()
-		l := len(str)
-
-		// 只计算前8个字节
-		if l > 8 {
-			l = 8
-		}
-
-		// 将字符串转换为uint64
-		for i := 0; i < l; i++ {
-			shift := uint(64 - 8 - i*8)
-			hash |= uint64(str[i]) << shift
-		}
-
-		score = float64(hash)
-
-	case reflect.Slice:
-		// 将[]byte转换为uint64
-		if val.Type().ConvertibleTo(typeOfBytes) {
-			var hash uint64
-			data := val.Convert(typeOfBytes).Interface().([]byte)
-
-			l := len(data)
-
-			// only use first 8 bytes
-			if l > 8 {
-				l = 8
-			}
-
-			// Consider str as a Big-Endian uint64.
-			for i := 0; i < l; i++ {
-				shift := uint(64 - 8 - i*8)
-				hash |= uint64(data[i]) << shift
-			}
-
-			score = float64(hash)
-		}
+	case reflect.String, reflect.Slice:
+		// 字节类的 key 没有分数，总是通过 CompareString、CompareBytes 比较。
+		// float64 只有 53 位精度，把前 8 个字节转换成分数会让不同的前缀得到相同的分数。
 	}
 
 	return
 }
 
-// kind 根据keyType获取kind
-func (kt keyType) kind() reflect.Kind {
+// kind 根据KeyType获取kind
+func (kt KeyType) kind() reflect.Kind {
 	return reflect.Kind(kt)
 }
@@ -0,0 +1,163 @@
+package skipList
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareBytes(t *testing.T) {
+	cases := [][2]string{
+		{"", ""},
+		{"", "a"},
+		{"a", "a\x00"},
+		{"abcdefgh", "abcdefgh"},
+		{"abcdefgh", "abcdefghi"},
+		{"abcdefg", "abcdefghi"},
+		{"abcdefgh\x00", "abcdefgh"},
+		{"https://example.com/a/1", "https://example.com/a/2"},
+		{"\xff\xff\xff\xff\xff\xff\xff\xfe", "\xff\xff\xff\xff\xff\xff\xff\xff"},
+	}
+	for _, c := range cases {
+		want := strings.Compare(c[0], c[1])
+		if got := CompareString(c[0], c[1]); got != want {
+			t.Errorf("CompareString(%q, %q) = %d, want %d", c[0], c[1], got, want)
+		}
+		if got := CompareString(c[1], c[0]); got != -want {
+			t.Errorf("CompareString(%q, %q) = %d, want %d", c[1], c[0], got, -want)
+		}
+	}
+}
+
+func FuzzCompareBytes(f *testing.F) {
+	f.Add([]byte(""), []byte(""))
+	f.Add([]byte("a"), []byte("a\x00"))
+	f.Add([]byte("abcdefgh"), []byte("abcdefghi"))
+	f.Add([]byte("https://example.com/x"), []byte("https://example.com/y"))
+	f.Fuzz(func(t *testing.T, a, b []byte) {
+		if got, want := CompareBytes(a, b), bytes.Compare(a, b); got != want {
+			t.Fatalf("CompareBytes(%q, %q) = %d, want %d", a, b, got, want)
+		}
+		if got, want := CompareString(string(a), string(b)), bytes.Compare(a, b); got != want {
+			t.Fatalf("CompareString(%q, %q) = %d, want %d", a, b, got, want)
+		}
+	})
+}
+
+// 前 8 个字节相同的 key 必须按完整内容排序
+func TestComparable_LongPrefix(t *testing.T) {
+	a := assert.New(t)
+	list := NewComparable[any, int](String)
+	bytesList := NewComparable[any, int](Bytes)
+	for i := 99; i >= 0; i-- {
+		key := fmt.Sprintf("https://example.com/item/%03d", i)
+		list.Set(key, i)
+		bytesList.Set([]byte(key), i)
+	}
+	i := 0
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		a.Equal(i, elem.Value)
+		a.Equal(0.0, elem.Score())
+		i++
+	}
+	a.Equal(100, i)
+	a.Equal(42, bytesList.MustGetValue([]byte("https://example.com/item/042")))
+}
+
+func BenchmarkStringKeys(b *testing.B) {
+	keys := make([]string, benchSize)
+	for i, n := range benchKeys() {
+		keys[i] = fmt.Sprintf("https://example.com/catalog/item/%08d", n)
+	}
+	b.Run("Generic", func(b *testing.B) {
+		list := New[string, int]()
+		for i := 0; i < b.N; i++ {
+			list.Set(keys[i%benchSize], i)
+		}
+	})
+	b.Run("Comparable", func(b *testing.B) {
+		list := NewComparable[any, int](String)
+		for i := 0; i < b.N; i++ {
+			list.Set(keys[i%benchSize], i)
+		}
+	})
+}
+
+type scoredItem struct {
+	name  string
+	score float64
+}
+
+func (s scoredItem) Score() float64 {
+	return s.score
+}
+
+func TestComparators(t *testing.T) {
+	a := assert.New(t)
+
+	desc := NewComparable[any, int](Reverse[any](Int))
+	for i := 0; i < 5; i++ {
+		desc.Set(i, i)
+	}
+	a.Equal([]any{4, 3, 2, 1, 0}, collectKeys(desc.All()))
+	a.Equal(-4.0, desc.Front().Score())
+	a.Equal(Int, Reverse(Reverse[any](Int)))
+
+	byLen := NewComparable[string, int](LessThanFunc(func(a, b string) bool {
+		return len(a) < len(b)
+	}))
+	byLen.Set("ccc", 3)
+	byLen.Set("a", 1)
+	byLen.Set("bb", 2)
+	byLen.Set("dd", 4)
+	a.Equal([]string{"a", "bb", "ccc"}, collectKeys(byLen.All()))
+	a.Equal(4, byLen.MustGetValue("xx"))
+
+	type event struct {
+		at time.Time
+		id uint64
+	}
+	base := time.Date(2024, 9, 7, 22, 0, 0, 0, time.UTC)
+	events := NewComparable[event, struct{}](Composite(
+		Field(func(e event) time.Time { return e.at }, Time),
+		Field(func(e event) uint64 { return e.id }, Reverse[uint64](Number[uint64]{})),
+	))
+	events.Set(event{base.Add(time.Nanosecond), 1}, struct{}{})
+	events.Set(event{base, 1}, struct{}{})
+	events.Set(event{base, 2}, struct{}{})
+	events.Set(event{base.Add(-time.Second), 9}, struct{}{})
+
+	var ids []uint64
+	for e := range events.All() {
+		ids = append(ids, e.id)
+	}
+	a.Equal([]uint64{9, 2, 1, 1}, ids)
+	a.Equal(float64(base.Unix()), events.Get(event{base, 2}).Score())
+	a.Equal(1, events.Count(event{base, 5}, event{base, 2}))
+
+	big1, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	big2 := new(big.Int).Add(big1, big.NewInt(1))
+	bigs := NewComparable[*big.Int, int](BigInt)
+	bigs.Set(big2, 2)
+	bigs.Set(big1, 1)
+	bigs.Set(big.NewInt(-1), 0)
+	a.Equal([]int{0, 1, 2}, collectValues(bigs.All()))
+	a.Equal(bigs.Front().Next().Score(), bigs.Back().Score())
+
+	items := NewComparable[scoredItem, int](ByScore(func(a, b scoredItem) int {
+		return strings.Compare(a.name, b.name)
+	}))
+	items.Set(scoredItem{"b", 1}, 0)
+	items.Set(scoredItem{"a", 1}, 0)
+	items.Set(scoredItem{"c", 0.5}, 0)
+	var names []string
+	for item := range items.RangeByScore(1, 1) {
+		names = append(names, item.name)
+	}
+	a.Equal([]string{"a", "b"}, names)
+}
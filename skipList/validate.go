@@ -0,0 +1,151 @@
+package skipList
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidStructure Validate 发现跳表的结构不一致
+var ErrInvalidStructure = errors.New("skiplist: 结构不一致")
+
+// Validate 检查跳表的结构，返回发现的第一个问题，错误包装了 ErrInvalidStructure。
+// 检查的内容包括：第 0 层按 key 严格递增且元素个数等于 Len()；
+// 每一层恰好链接层数高于该层的所有元素，跨度与排名一致；
+// prev、prevTopLevel、back 以及元素所属的列表和分数正确。
+// 时间复杂度为 O(n * level)，用于测试和排查问题。
+func (list *SkipList[K, V]) Validate() error {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: "+format, append([]any{ErrInvalidStructure}, args...)...)
+	}
+
+	max := len(list.levels)
+
+	if len(list.spans) != max {
+		return invalid("头节点有 %d 层，但有 %d 个跨度", max, len(list.spans))
+	}
+
+	// 每一层最后经过的元素及其排名，nil 表示头节点
+	last := make([]*Element[K, V], max)
+	lastRanks := make([]int, max)
+	var prev *Element[K, V]
+	rank := 0
+
+	for elem := list.levels[0]; elem != nil; elem = elem.levels[0] {
+		rank++
+		level := elem.Level()
+
+		if rank > list.length {
+			return invalid("第 0 层的元素多于 Len() = %d", list.length)
+		}
+
+		if level == 0 || level > max {
+			return invalid("key %v 的层数 %d 不在 [1, %d] 内", elem.key, level, max)
+		}
+
+		if len(elem.spans) != level {
+			return invalid("key %v 有 %d 层，但有 %d 个跨度", elem.key, level, len(elem.spans))
+		}
+
+		if elem.list != list {
+			return invalid("key %v 不属于该列表", elem.key)
+		}
+
+		if list.scoreOf != nil && list.scoreOf(elem.key) != elem.score {
+			return invalid("key %v 的分数为 %v，应为 %v", elem.key, elem.score, list.scoreOf(elem.key))
+		}
+
+		if prev != nil && list.compare(elem.score, elem.key, prev) <= 0 {
+			return invalid("key %v 没有大于前一个元素 %v", elem.key, prev.key)
+		}
+
+		if elem.prev != prev {
+			return invalid("key %v 的 prev 错误", elem.key)
+		}
+
+		if elem.prevTopLevel != last[level-1] {
+			return invalid("key %v 的 prevTopLevel 错误", elem.key)
+		}
+
+		for i := 0; i < level; i++ {
+			header := &list.elementHeader
+			if last[i] != nil {
+				header = &last[i].elementHeader
+			}
+
+			if header.levels[i] != elem {
+				return invalid("第 %d 层没有链接到 key %v", i, elem.key)
+			}
+
+			if span := rank - lastRanks[i]; header.spans[i] != span {
+				return invalid("第 %d 层到 key %v 的跨度为 %d，应为 %d", i, elem.key, header.spans[i], span)
+			}
+
+			last[i] = elem
+			lastRanks[i] = rank
+		}
+
+		prev = elem
+	}
+
+	if rank != list.length {
+		return invalid("第 0 层有 %d 个元素，但 Len() = %d", rank, list.length)
+	}
+
+	if list.back != prev {
+		return invalid("back 错误")
+	}
+
+	for i, elem := range last {
+		header := &list.elementHeader
+		if elem != nil {
+			header = &elem.elementHeader
+		}
+
+		if header.levels[i] != nil {
+			return invalid("第 %d 层链接了不在第 0 层的元素", i)
+		}
+	}
+
+	return nil
+}
+
+// Stats 跳表的结构统计
+type Stats struct {
+	Len      int // 元素个数
+	MaxLevel int // 允许的最大层数
+	Height   int // 元素的最高层数
+	// LevelCounts[i] 为层数是 i+1 的元素个数
+	LevelCounts []int
+	// AvgSearchPath 查找一个已存在的 key 平均需要比较的元素个数
+	AvgSearchPath float64
+}
+
+// Stats 返回跳表的结构统计，需要查找每一个元素，时间复杂度为 O(n log n)。
+func (list *SkipList[K, V]) Stats() Stats {
+	stats := Stats{Len: list.length, MaxLevel: list.maxLevel}
+	counts := make([]int, len(list.levels))
+
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		counts[elem.Level()-1]++
+		stats.Height = max(stats.Height, elem.Level())
+	}
+
+	stats.LevelCounts = counts[:stats.Height]
+
+	if list.length == 0 {
+		return stats
+	}
+
+	compared := 0
+
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		score, key := elem.score, elem.key
+		list.findFirstRank(func(next *Element[K, V]) bool {
+			compared++
+			return list.compare(score, key, next) <= 0
+		})
+	}
+
+	stats.AvgSearchPath = float64(compared) / float64(list.length)
+	return stats
+}
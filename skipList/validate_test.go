@@ -0,0 +1,117 @@
+package skipList
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkValid 用 Validate 校验列表的结构
+func checkValid[K, V any](t *testing.T, list *SkipList[K, V]) {
+	t.Helper()
+	if err := list.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func levelsOf[K, V any](list *SkipList[K, V]) []int {
+	var levels []int
+	for elem := list.Front(); elem != nil; elem = elem.Next() {
+		levels = append(levels, elem.Level())
+	}
+	return levels
+}
+
+func TestNewWithOptions(t *testing.T) {
+	a := assert.New(t)
+
+	// 相同的种子得到相同的结构
+	l1 := NewWithOptions[int, int](Options{Seed: 7})
+	l2 := NewWithOptions[int, int](Options{Seed: 7})
+	for i := 0; i < 1000; i++ {
+		l1.Set(i, i)
+		l2.Set(i, i)
+	}
+	a.Equal(levelsOf(l1), levelsOf(l2))
+	checkValid(t, l1)
+
+	// 概率越小，层数越低
+	low := NewWithOptions[int, int](Options{Seed: 7, Probability: 0.1, MaxLevel: 8})
+	for i := 0; i < 1000; i++ {
+		low.Set(i, i)
+	}
+	checkValid(t, low)
+	a.Less(low.Stats().Height, l1.Stats().Height)
+	a.LessOrEqual(low.Stats().Height, 8)
+	a.Greater(low.Stats().LevelCounts[0], 850)
+
+	a.Nil(NewWithOptions[int, int](Options{Probability: 1}))
+	a.Nil(NewWithOptions[int, int](Options{Probability: -0.5}))
+	a.Nil(NewWithOptions[int, int](Options{MaxLevel: -1}))
+	a.ErrorIs(l1.SetProbability(0), ErrProbability)
+	a.NoError(l1.SetProbability(0.25))
+}
+
+func TestValidate(t *testing.T) {
+	a := assert.New(t)
+	list := NewWithOptions[int, int](Options{Seed: 1})
+	a.NoError(list.Validate())
+
+	for i := 0; i < 100; i++ {
+		list.Set(i, i)
+	}
+	a.NoError(list.Validate())
+
+	elem := list.Get(50)
+	prev := elem.prev
+	elem.prev = nil
+	a.ErrorIs(list.Validate(), ErrInvalidStructure)
+	elem.prev = prev
+
+	top := elem.prevTopLevel
+	elem.prevTopLevel = list.Front()
+	a.ErrorIs(list.Validate(), ErrInvalidStructure)
+	elem.prevTopLevel = top
+
+	elem.spans[0]++
+	a.ErrorIs(list.Validate(), ErrInvalidStructure)
+	elem.spans[0]--
+
+	list.length++
+	a.ErrorIs(list.Validate(), ErrInvalidStructure)
+	list.length--
+
+	elem.key, elem.levels[0].key = elem.levels[0].key, elem.key
+	a.ErrorIs(list.Validate(), ErrInvalidStructure)
+	elem.key, elem.levels[0].key = elem.levels[0].key, elem.key
+
+	a.NoError(list.Validate())
+}
+
+func TestStats(t *testing.T) {
+	a := assert.New(t)
+	list := NewWithOptions[int, int](Options{Seed: 1, MaxLevel: 16})
+
+	stats := list.Stats()
+	a.Equal(0, stats.Len)
+	a.Equal(16, stats.MaxLevel)
+	a.Empty(stats.LevelCounts)
+
+	for i := 0; i < 4096; i++ {
+		list.Set(i, i)
+	}
+
+	stats = list.Stats()
+	a.Equal(4096, stats.Len)
+	a.Len(stats.LevelCounts, stats.Height)
+
+	sum := 0
+	for _, n := range stats.LevelCounts {
+		sum += n
+	}
+	a.Equal(4096, sum)
+
+	// 期望的比较次数约为 2 * log2(n)
+	a.Greater(stats.AvgSearchPath, 5.0)
+	a.Less(stats.AvgSearchPath, 40.0)
+}
@@ -0,0 +1,505 @@
+package skipList
+
+import (
+	"cmp"
+	"errors"
+	"math"
+)
+
+// ZAddFlag ZSet.Add 的选项，对应 Redis ZADD 的 NX、XX、GT、LT、INCR
+type ZAddFlag uint8
+
+const (
+	ZAddNX   ZAddFlag = 1 << iota // 只添加新成员，不更新已有成员
+	ZAddXX                        // 只更新已有成员，不添加新成员
+	ZAddGT                        // 新分数大于当前分数时才更新
+	ZAddLT                        // 新分数小于当前分数时才更新
+	ZAddIncr                      // 分数作为增量累加到当前分数上
+)
+
+// Aggregate ZUnion、ZInter 合并分数的方式
+type Aggregate int
+
+const (
+	AggregateSum Aggregate = iota
+	AggregateMin
+	AggregateMax
+)
+
+var (
+	// ErrZAddFlags ZAdd 选项冲突：NX 与 XX，或 GT、LT、NX 同时使用
+	ErrZAddFlags = errors.New("skiplist: ZAdd 选项冲突")
+	// ErrNaNScore 分数为 NaN，例如 +inf 与 -inf 相加
+	ErrNaNScore = errors.New("skiplist: 分数不能为 NaN")
+	// ErrZWeights weights 的个数与 sets 不一致
+	ErrZWeights = errors.New("skiplist: weights 的个数必须与 sets 相同")
+	// ErrNoZSet 没有传入任何 ZSet
+	ErrNoZSet = errors.New("skiplist: 至少需要一个 ZSet")
+)
+
+// ZMember ZSet 的成员及其分数
+type ZMember[M comparable] struct {
+	Member M
+	Score  float64
+}
+
+// ScoreBound 分数区间的端点，Exclusive 为 true 时不包含端点（Redis 中的 "(score"）
+// 无穷大使用 math.Inf 表示。
+type ScoreBound struct {
+	Value     float64
+	Exclusive bool
+}
+
+// LexBound 字典序区间的端点
+// Unbounded 为 true 时表示无穷，作为下界时等同于 Redis 的 "-"，作为上界时等同于 "+"。
+type LexBound[M any] struct {
+	Value     M
+	Exclusive bool
+	Unbounded bool
+}
+
+// ZSet Redis 风格的有序集合
+// 成员唯一，分数可以重复，元素按 (分数, 成员) 排序。
+// 内部由成员到跳表节点的字典和以 (分数, 成员) 为 key 的 SkipList 组成。
+// 与 SkipList 一样不是并发安全的。
+type ZSet[M comparable] struct {
+	dict    map[M]*zsetElement[M]
+	list    *SkipList[zsetKey[M], struct{}]
+	compare func(a, b M) int
+}
+
+// zsetKey 跳表中的 key，分数作为 SkipList 的 score，分数相同时按成员排序
+type zsetKey[M comparable] struct {
+	member M
+	score  float64
+}
+
+type zsetElement[M comparable] = Element[zsetKey[M], struct{}]
+
+type zsetComparable[M comparable] struct {
+	compare func(a, b M) int
+}
+
+func (c zsetComparable[M]) Compare(a, b zsetKey[M]) int {
+	return c.compare(a.member, b.member)
+}
+
+func (c zsetComparable[M]) CalcScore(key zsetKey[M]) float64 {
+	return key.score
+}
+
+// NewZSet 创建一个成员按自然顺序比较的 ZSet
+func NewZSet[M cmp.Ordered]() *ZSet[M] {
+	return NewZSetFunc[M](cmp.Compare[M])
+}
+
+// NewZSetFunc 使用 compare 比较成员创建 ZSet
+// 分数相同的成员以及字典序区间都按 compare 的顺序排列。
+func NewZSetFunc[M comparable](compare func(a, b M) int) *ZSet[M] {
+	return &ZSet[M]{
+		dict:    make(map[M]*zsetElement[M]),
+		list:    NewComparable[zsetKey[M], struct{}](zsetComparable[M]{compare: compare}),
+		compare: compare,
+	}
+}
+
+func memberOf[M comparable](elem *zsetElement[M]) M {
+	return elem.key.member
+}
+
+func zmemberOf[M comparable](elem *zsetElement[M]) ZMember[M] {
+	return ZMember[M]{Member: memberOf[M](elem), Score: elem.score}
+}
+
+func (z *ZSet[M]) insert(member M, score float64) {
+	z.dict[member] = z.list.Set(zsetKey[M]{member: member, score: score}, struct{}{})
+}
+
+func (z *ZSet[M]) delete(elem *zsetElement[M]) {
+	delete(z.dict, memberOf[M](elem))
+	z.list.RemoveElement(elem)
+}
+
+// Len 成员个数（ZCARD）
+func (z *ZSet[M]) Len() int {
+	return z.list.Len()
+}
+
+// Add 添加成员或更新成员的分数（ZADD）
+// 返回操作后成员的分数，以及是否添加了成员或修改了分数。
+// 由于选项限制没有执行任何操作时，返回成员当前的分数（不存在时为 0）和 false。
+func (z *ZSet[M]) Add(member M, score float64, flags ZAddFlag) (float64, bool, error) {
+	nx, xx := flags&ZAddNX != 0, flags&ZAddXX != 0
+	gt, lt := flags&ZAddGT != 0, flags&ZAddLT != 0
+	if nx && xx || nx && (gt || lt) || gt && lt {
+		return 0, false, ErrZAddFlags
+	}
+	if math.IsNaN(score) {
+		return 0, false, ErrNaNScore
+	}
+
+	elem, ok := z.dict[member]
+	if !ok {
+		if xx {
+			return 0, false, nil
+		}
+		z.insert(member, score)
+		return score, true, nil
+	}
+
+	cur := elem.score
+	if nx {
+		return cur, false, nil
+	}
+	if flags&ZAddIncr != 0 {
+		score += cur
+		if math.IsNaN(score) {
+			return cur, false, ErrNaNScore
+		}
+	}
+	if score == cur || gt && score < cur || lt && score > cur {
+		return cur, false, nil
+	}
+	z.list.RemoveElement(elem)
+	z.insert(member, score)
+	return score, true, nil
+}
+
+// IncrBy 为成员的分数加上 incr，成员不存在时以 incr 为分数添加（ZINCRBY）
+func (z *ZSet[M]) IncrBy(member M, incr float64) (float64, error) {
+	score, _, err := z.Add(member, incr, ZAddIncr)
+	return score, err
+}
+
+// Remove 删除成员，返回删除的个数（ZREM）
+func (z *ZSet[M]) Remove(members ...M) int {
+	removed := 0
+	for _, member := range members {
+		if elem, ok := z.dict[member]; ok {
+			z.delete(elem)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Score 返回成员的分数（ZSCORE）
+func (z *ZSet[M]) Score(member M) (float64, bool) {
+	if elem, ok := z.dict[member]; ok {
+		return elem.score, true
+	}
+	return 0, false
+}
+
+// Rank 返回成员按分数从小到大的排名，从 0 开始（ZRANK）
+func (z *ZSet[M]) Rank(member M) (int, bool) {
+	elem, ok := z.dict[member]
+	if !ok {
+		return 0, false
+	}
+	return z.list.Rank(elem.key)
+}
+
+// RevRank 返回成员按分数从大到小的排名，从 0 开始（ZREVRANK）
+func (z *ZSet[M]) RevRank(member M) (int, bool) {
+	rank, ok := z.Rank(member)
+	if !ok {
+		return 0, false
+	}
+	return z.Len() - 1 - rank, true
+}
+
+// Range 返回排名在 [start, stop] 内的成员，按分数从小到大排列（ZRANGE）
+// 下标可以为负数，-1 表示最后一个成员。
+func (z *ZSet[M]) Range(start, stop int) []ZMember[M] {
+	start, stop, ok := normalizeRank(start, stop, z.Len())
+	if !ok {
+		return nil
+	}
+	result := make([]ZMember[M], 0, stop-start+1)
+	for elem := z.list.GetByRank(start); len(result) < cap(result); elem = elem.Next() {
+		result = append(result, zmemberOf[M](elem))
+	}
+	return result
+}
+
+// RevRange 返回按分数从大到小排名在 [start, stop] 内的成员（ZREVRANGE）
+func (z *ZSet[M]) RevRange(start, stop int) []ZMember[M] {
+	start, stop, ok := normalizeRank(start, stop, z.Len())
+	if !ok {
+		return nil
+	}
+	result := make([]ZMember[M], 0, stop-start+1)
+	for elem := z.list.GetByRank(z.Len() - 1 - start); len(result) < cap(result); elem = elem.Prev() {
+		result = append(result, zmemberOf[M](elem))
+	}
+	return result
+}
+
+// RemoveRangeByRank 删除排名在 [start, stop] 内的成员，返回删除的个数（ZREMRANGEBYRANK）
+func (z *ZSet[M]) RemoveRangeByRank(start, stop int) int {
+	start, stop, ok := normalizeRank(start, stop, z.Len())
+	if !ok {
+		return 0
+	}
+	elem := z.list.GetByRank(start)
+	for i := start; i <= stop; i++ {
+		delete(z.dict, memberOf[M](elem))
+		elem = elem.Next()
+	}
+	return z.list.RemoveRangeByRank(start, stop)
+}
+
+// forward 返回排名为 rank（从 1 开始）的元素之后第 offset 个元素
+func (z *ZSet[M]) forward(rank, offset int) *zsetElement[M] {
+	if offset < 0 {
+		return nil
+	}
+	return z.list.GetByRank(rank - 1 + offset)
+}
+
+// backward 返回排名为 rank（从 1 开始）的元素之前第 offset 个元素
+func (z *ZSet[M]) backward(rank, offset int) *zsetElement[M] {
+	if offset < 0 || rank-1-offset < 0 {
+		return nil
+	}
+	return z.list.GetByRank(rank - 1 - offset)
+}
+
+// collect 从 elem 开始沿 next 方向收集满足 in 的元素，最多 count 个
+// count 为负数时不限制个数，与 Redis 的 LIMIT 相同。
+func collect[M comparable](elem *zsetElement[M], next func(*zsetElement[M]) *zsetElement[M], in func(*zsetElement[M]) bool, count int) []ZMember[M] {
+	var result []ZMember[M]
+	for ; elem != nil && count != 0 && in(elem); elem = next(elem) {
+		result = append(result, zmemberOf[M](elem))
+		count--
+	}
+	return result
+}
+
+func (b ScoreBound) aboveMin(score float64) bool {
+	if b.Exclusive {
+		return score > b.Value
+	}
+	return score >= b.Value
+}
+
+func (b ScoreBound) belowMax(score float64) bool {
+	if b.Exclusive {
+		return score < b.Value
+	}
+	return score <= b.Value
+}
+
+// RangeByScore 返回分数在 [min, max] 内的成员，按分数从小到大排列（ZRANGEBYSCORE）
+// 跳过前 offset 个，最多返回 count 个，count 为负数时不限制。
+func (z *ZSet[M]) RangeByScore(min, max ScoreBound, offset, count int) []ZMember[M] {
+	_, rank := z.list.findFirstRank(func(elem *zsetElement[M]) bool {
+		return min.aboveMin(elem.score)
+	})
+	return collect[M](z.forward(rank, offset), (*zsetElement[M]).Next, func(elem *zsetElement[M]) bool {
+		return max.belowMax(elem.score)
+	}, count)
+}
+
+// RevRangeByScore 返回分数在 [min, max] 内的成员，按分数从大到小排列（ZREVRANGEBYSCORE）
+func (z *ZSet[M]) RevRangeByScore(max, min ScoreBound, offset, count int) []ZMember[M] {
+	_, rank := z.list.findLastRank(func(elem *zsetElement[M]) bool {
+		return max.belowMax(elem.score)
+	})
+	return collect[M](z.backward(rank, offset), (*zsetElement[M]).Prev, func(elem *zsetElement[M]) bool {
+		return min.aboveMin(elem.score)
+	}, count)
+}
+
+func (z *ZSet[M]) aboveLexMin(min LexBound[M], member M) bool {
+	if min.Unbounded {
+		return true
+	}
+	if c := z.compare(member, min.Value); c != 0 {
+		return c > 0
+	}
+	return !min.Exclusive
+}
+
+func (z *ZSet[M]) belowLexMax(max LexBound[M], member M) bool {
+	if max.Unbounded {
+		return true
+	}
+	if c := z.compare(member, max.Value); c != 0 {
+		return c < 0
+	}
+	return !max.Exclusive
+}
+
+// RangeByLex 返回成员在 [min, max] 内的成员，按成员从小到大排列（ZRANGEBYLEX）
+// 与 Redis 相同，只有所有成员的分数都相同时结果才有意义。
+func (z *ZSet[M]) RangeByLex(min, max LexBound[M], offset, count int) []ZMember[M] {
+	_, rank := z.list.findFirstRank(func(elem *zsetElement[M]) bool {
+		return z.aboveLexMin(min, memberOf[M](elem))
+	})
+	return collect[M](z.forward(rank, offset), (*zsetElement[M]).Next, func(elem *zsetElement[M]) bool {
+		return z.belowLexMax(max, memberOf[M](elem))
+	}, count)
+}
+
+// RevRangeByLex 返回成员在 [min, max] 内的成员，按成员从大到小排列（ZREVRANGEBYLEX）
+func (z *ZSet[M]) RevRangeByLex(max, min LexBound[M], offset, count int) []ZMember[M] {
+	_, rank := z.list.findLastRank(func(elem *zsetElement[M]) bool {
+		return z.belowLexMax(max, memberOf[M](elem))
+	})
+	return collect[M](z.backward(rank, offset), (*zsetElement[M]).Prev, func(elem *zsetElement[M]) bool {
+		return z.aboveLexMin(min, memberOf[M](elem))
+	}, count)
+}
+
+// Count 返回分数在 [min, max] 内的成员个数（ZCOUNT）
+func (z *ZSet[M]) Count(min, max ScoreBound) int {
+	_, first := z.list.findFirstRank(func(elem *zsetElement[M]) bool {
+		return min.aboveMin(elem.score)
+	})
+	_, last := z.list.findLastRank(func(elem *zsetElement[M]) bool {
+		return max.belowMax(elem.score)
+	})
+	return countBetween(first, last)
+}
+
+// LexCount 返回成员在 [min, max] 内的成员个数（ZLEXCOUNT）
+func (z *ZSet[M]) LexCount(min, max LexBound[M]) int {
+	_, first := z.list.findFirstRank(func(elem *zsetElement[M]) bool {
+		return z.aboveLexMin(min, memberOf[M](elem))
+	})
+	_, last := z.list.findLastRank(func(elem *zsetElement[M]) bool {
+		return z.belowLexMax(max, memberOf[M](elem))
+	})
+	return countBetween(first, last)
+}
+
+// countBetween 排名在 [first, last] 内的元素个数
+func countBetween(first, last int) int {
+	if last < first {
+		return 0
+	}
+	return last - first + 1
+}
+
+// PopMin 删除并返回分数最小的 count 个成员（ZPOPMIN）
+func (z *ZSet[M]) PopMin(count int) []ZMember[M] {
+	var result []ZMember[M]
+	for ; count > 0 && z.Len() > 0; count-- {
+		elem := z.list.Front()
+		result = append(result, zmemberOf[M](elem))
+		z.delete(elem)
+	}
+	return result
+}
+
+// PopMax 删除并返回分数最大的 count 个成员（ZPOPMAX）
+func (z *ZSet[M]) PopMax(count int) []ZMember[M] {
+	var result []ZMember[M]
+	for ; count > 0 && z.Len() > 0; count-- {
+		elem := z.list.Back()
+		result = append(result, zmemberOf[M](elem))
+		z.delete(elem)
+	}
+	return result
+}
+
+// weightedScore 与 Redis 相同，inf * 0 的结果为 0
+func weightedScore(score, weight float64) float64 {
+	if r := score * weight; !math.IsNaN(r) {
+		return r
+	}
+	return 0
+}
+
+// aggregate 与 Redis 相同，+inf 与 -inf 相加的结果为 0
+func (agg Aggregate) aggregate(acc, score float64) float64 {
+	switch agg {
+	case AggregateMin:
+		return math.Min(acc, score)
+	case AggregateMax:
+		return math.Max(acc, score)
+	default:
+		if r := acc + score; !math.IsNaN(r) {
+			return r
+		}
+		return 0
+	}
+}
+
+func checkZSets[M comparable](sets []*ZSet[M], weights []float64) error {
+	if len(sets) == 0 {
+		return ErrNoZSet
+	}
+	if weights != nil && len(weights) != len(sets) {
+		return ErrZWeights
+	}
+	return nil
+}
+
+func weightAt(weights []float64, i int) float64 {
+	if weights == nil {
+		return 1
+	}
+	return weights[i]
+}
+
+// ZUnion 返回多个 ZSet 的并集（ZUNION）
+// 成员的分数乘以所在集合的权重后按 agg 合并，weights 为 nil 时权重都为 1。
+// 结果使用第一个集合的成员比较函数。
+func ZUnion[M comparable](sets []*ZSet[M], weights []float64, agg Aggregate) (*ZSet[M], error) {
+	if err := checkZSets(sets, weights); err != nil {
+		return nil, err
+	}
+	scores := make(map[M]float64)
+	for i, set := range sets {
+		w := weightAt(weights, i)
+		for elem := set.list.Front(); elem != nil; elem = elem.Next() {
+			member, score := memberOf[M](elem), weightedScore(elem.score, w)
+			if acc, ok := scores[member]; ok {
+				score = agg.aggregate(acc, score)
+			}
+			scores[member] = score
+		}
+	}
+	result := NewZSetFunc[M](sets[0].compare)
+	for member, score := range scores {
+		result.insert(member, score)
+	}
+	return result, nil
+}
+
+// ZInter 返回多个 ZSet 的交集（ZINTER），参数含义与 ZUnion 相同
+func ZInter[M comparable](sets []*ZSet[M], weights []float64, agg Aggregate) (*ZSet[M], error) {
+	if err := checkZSets(sets, weights); err != nil {
+		return nil, err
+	}
+	// 遍历最小的集合，在其他集合中查找
+	smallest := sets[0]
+	for _, set := range sets[1:] {
+		if set.Len() < smallest.Len() {
+			smallest = set
+		}
+	}
+	result := NewZSetFunc[M](sets[0].compare)
+next:
+	for elem := smallest.list.Front(); elem != nil; elem = elem.Next() {
+		member := memberOf[M](elem)
+		var acc float64
+		for i, set := range sets {
+			score, ok := set.Score(member)
+			if !ok {
+				continue next
+			}
+			score = weightedScore(score, weightAt(weights, i))
+			if i == 0 {
+				acc = score
+			} else {
+				acc = agg.aggregate(acc, score)
+			}
+		}
+		result.insert(member, acc)
+	}
+	return result, nil
+}
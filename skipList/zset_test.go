@@ -0,0 +1,215 @@
+package skipList
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func members[M comparable](zs []ZMember[M]) []M {
+	result := make([]M, 0, len(zs))
+	for _, z := range zs {
+		result = append(result, z.Member)
+	}
+	return result
+}
+
+func newLeaderboard() *ZSet[string] {
+	z := NewZSet[string]()
+	z.Add("alice", 100, 0)
+	z.Add("bob", 80, 0)
+	z.Add("carol", 100, 0)
+	z.Add("dave", 60, 0)
+	z.Add("eve", 90, 0)
+	return z
+}
+
+func TestZSet_Add(t *testing.T) {
+	a := assert.New(t)
+	z := NewZSet[string]()
+
+	score, changed, err := z.Add("a", 1, 0)
+	a.NoError(err)
+	a.True(changed)
+	a.Equal(1.0, score)
+
+	_, changed, _ = z.Add("a", 5, ZAddNX)
+	a.False(changed)
+	_, changed, _ = z.Add("b", 5, ZAddXX)
+	a.False(changed)
+	a.Equal(1, z.Len())
+
+	_, changed, _ = z.Add("a", 0, ZAddGT)
+	a.False(changed)
+	score, changed, _ = z.Add("a", 3, ZAddGT)
+	a.True(changed)
+	a.Equal(3.0, score)
+	_, changed, _ = z.Add("a", 4, ZAddLT)
+	a.False(changed)
+
+	score, _, _ = z.Add("a", 2, ZAddIncr)
+	a.Equal(5.0, score)
+	score, _ = z.IncrBy("c", 7)
+	a.Equal(7.0, score)
+	// GT 不影响添加新成员
+	_, changed, _ = z.Add("d", -1, ZAddGT)
+	a.True(changed)
+
+	_, _, err = z.Add("a", 1, ZAddNX|ZAddXX)
+	a.ErrorIs(err, ErrZAddFlags)
+	_, _, err = z.Add("a", 1, ZAddGT|ZAddLT)
+	a.ErrorIs(err, ErrZAddFlags)
+	_, _, err = z.Add("a", math.NaN(), 0)
+	a.ErrorIs(err, ErrNaNScore)
+	z.Add("inf", math.Inf(1), 0)
+	_, _, err = z.Add("inf", math.Inf(-1), ZAddIncr)
+	a.ErrorIs(err, ErrNaNScore)
+
+	a.Equal(2, z.Remove("a", "d", "missing"))
+	_, ok := z.Score("a")
+	a.False(ok)
+}
+
+func TestZSet_Rank(t *testing.T) {
+	a := assert.New(t)
+	z := newLeaderboard()
+
+	// 分数相同时按成员排序
+	a.Equal([]string{"dave", "bob", "eve", "alice", "carol"}, members(z.Range(0, -1)))
+	rank, ok := z.Rank("alice")
+	a.True(ok)
+	a.Equal(3, rank)
+	rank, _ = z.RevRank("alice")
+	a.Equal(1, rank)
+	_, ok = z.Rank("zed")
+	a.False(ok)
+
+	a.Equal([]string{"carol", "alice", "eve"}, members(z.RevRange(0, 2)))
+	a.Equal([]string{"alice", "carol"}, members(z.Range(-2, 100)))
+	a.Nil(z.Range(3, 1))
+	a.Nil(z.Range(10, 20))
+
+	a.Equal(2, z.RemoveRangeByRank(1, 2))
+	a.Equal([]string{"dave", "alice", "carol"}, members(z.Range(0, -1)))
+	_, ok = z.Score("bob")
+	a.False(ok)
+}
+
+func TestZSet_RangeByScore(t *testing.T) {
+	a := assert.New(t)
+	z := newLeaderboard()
+	inf := math.Inf(1)
+
+	a.Equal([]string{"bob", "eve", "alice", "carol"},
+		members(z.RangeByScore(ScoreBound{Value: 80}, ScoreBound{Value: inf}, 0, -1)))
+	a.Equal([]string{"eve"},
+		members(z.RangeByScore(ScoreBound{Value: 80, Exclusive: true}, ScoreBound{Value: 100, Exclusive: true}, 0, -1)))
+	a.Equal([]string{"alice", "carol"},
+		members(z.RangeByScore(ScoreBound{Value: -inf}, ScoreBound{Value: inf}, 3, 2)))
+	a.Equal([]string{"carol", "alice", "eve"},
+		members(z.RevRangeByScore(ScoreBound{Value: inf}, ScoreBound{Value: 90}, 0, -1)))
+	a.Equal([]string{"eve", "bob"},
+		members(z.RevRangeByScore(ScoreBound{Value: 100, Exclusive: true}, ScoreBound{Value: 0}, 0, 2)))
+	a.Nil(z.RangeByScore(ScoreBound{Value: 101}, ScoreBound{Value: inf}, 0, -1))
+	a.Nil(z.RangeByScore(ScoreBound{Value: -inf}, ScoreBound{Value: inf}, 5, -1))
+
+	a.Equal(3, z.Count(ScoreBound{Value: 90}, ScoreBound{Value: 100}))
+	a.Equal(1, z.Count(ScoreBound{Value: 80, Exclusive: true}, ScoreBound{Value: 100, Exclusive: true}))
+	a.Equal(0, z.Count(ScoreBound{Value: 100}, ScoreBound{Value: 90}))
+}
+
+func TestZSet_RangeByLex(t *testing.T) {
+	a := assert.New(t)
+	z := NewZSet[string]()
+	for _, m := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		z.Add(m, 0, 0)
+	}
+	all := LexBound[string]{Unbounded: true}
+
+	a.Equal([]string{"a", "b", "c"}, members(z.RangeByLex(all, LexBound[string]{Value: "c"}, 0, -1)))
+	a.Equal([]string{"a", "b"}, members(z.RangeByLex(all, LexBound[string]{Value: "c", Exclusive: true}, 0, -1)))
+	a.Equal([]string{"c", "d", "e", "f"},
+		members(z.RangeByLex(LexBound[string]{Value: "aaa", Exclusive: true}, LexBound[string]{Value: "g", Exclusive: true}, 1, -1)))
+	a.Equal([]string{"g", "f"}, members(z.RevRangeByLex(all, all, 0, 2)))
+	a.Equal([]string{"e", "d"},
+		members(z.RevRangeByLex(LexBound[string]{Value: "e"}, LexBound[string]{Value: "c", Exclusive: true}, 0, -1)))
+	a.Equal(7, z.LexCount(all, all))
+	a.Equal(2, z.LexCount(LexBound[string]{Value: "b", Exclusive: true}, LexBound[string]{Value: "d"}))
+}
+
+func TestZSet_Pop(t *testing.T) {
+	a := assert.New(t)
+	z := newLeaderboard()
+
+	a.Equal([]ZMember[string]{{"dave", 60}, {"bob", 80}}, z.PopMin(2))
+	a.Equal([]ZMember[string]{{"carol", 100}}, z.PopMax(1))
+	a.Equal(2, z.Len())
+	a.Len(z.PopMax(10), 2)
+	a.Nil(z.PopMin(1))
+}
+
+func TestZSet_UnionInter(t *testing.T) {
+	a := assert.New(t)
+	z1 := NewZSet[string]()
+	z1.Add("a", 1, 0)
+	z1.Add("b", 2, 0)
+	z2 := NewZSet[string]()
+	z2.Add("b", 3, 0)
+	z2.Add("c", 4, 0)
+
+	union, err := ZUnion([]*ZSet[string]{z1, z2}, []float64{1, 2}, AggregateSum)
+	a.NoError(err)
+	a.Equal([]ZMember[string]{{"a", 1}, {"b", 8}, {"c", 8}}, union.Range(0, -1))
+
+	union, _ = ZUnion([]*ZSet[string]{z1, z2}, nil, AggregateMax)
+	score, _ := union.Score("b")
+	a.Equal(3.0, score)
+
+	inter, err := ZInter([]*ZSet[string]{z1, z2}, nil, AggregateMin)
+	a.NoError(err)
+	a.Equal([]ZMember[string]{{"b", 2}}, inter.Range(0, -1))
+
+	_, err = ZInter([]*ZSet[string]{z1, z2}, []float64{1}, AggregateSum)
+	a.ErrorIs(err, ErrZWeights)
+	_, err = ZUnion[string](nil, nil, AggregateSum)
+	a.ErrorIs(err, ErrNoZSet)
+}
+
+func TestZSet_Random(t *testing.T) {
+	a := assert.New(t)
+	z := NewZSet[int]()
+	scores := make(map[int]float64)
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 5000; i++ {
+		member := rnd.Intn(300)
+		if rnd.Intn(4) == 0 {
+			z.Remove(member)
+			delete(scores, member)
+			continue
+		}
+		score := float64(rnd.Intn(50))
+		z.Add(member, score, 0)
+		scores[member] = score
+	}
+
+	want := make([]ZMember[int], 0, len(scores))
+	for m, s := range scores {
+		want = append(want, ZMember[int]{Member: m, Score: s})
+	}
+	sort.Slice(want, func(i, j int) bool {
+		if want[i].Score != want[j].Score {
+			return want[i].Score < want[j].Score
+		}
+		return want[i].Member < want[j].Member
+	})
+	a.Equal(want, z.Range(0, -1))
+	for i, w := range want {
+		rank, ok := z.Rank(w.Member)
+		a.True(ok)
+		a.Equal(i, rank)
+	}
+}
@@ -0,0 +1,160 @@
+package gttype
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrValueExists BiMap 写入的 value 已经与另一个 key 关联
+var ErrValueExists = errors.New("BiMap: value 已经与其他 key 关联")
+
+// BiMap key 与 value 一一对应的双向 map
+// 遍历与 JSON 序列化按写入顺序进行。
+type BiMap[K, V comparable] interface {
+	json.Marshaler
+	json.Unmarshaler
+	// Put 写入键值对，val 已经与其他 key 关联时返回 ErrValueExists
+	Put(key K, val V) error
+	// ForcePut 写入键值对，val 已经与其他 key 关联时先删除原来的键值对
+	ForcePut(key K, val V)
+	Get(key K) (V, bool)
+	// GetKey 按 value 反查 key
+	GetKey(val V) (K, bool)
+	Remove(key K) bool
+	ContainsKey(key K) bool
+	ContainsValue(val V) bool
+	Len() int
+	Keys() []K
+	Values() []V
+	ForEach(fn func(key K, val V))
+	Clear()
+	// Inverse 返回 value 到 key 的视图，与当前 BiMap 共享数据
+	Inverse() BiMap[V, K]
+}
+
+type adkBiMap[K, V comparable] struct {
+	forward  LinkedHashMap[K, V]
+	backward LinkedHashMap[V, K]
+	inverse  *adkBiMap[V, K]
+}
+
+// NewBiMap 创建一个双向 map
+func NewBiMap[K, V comparable]() BiMap[K, V] {
+	m := &adkBiMap[K, V]{
+		forward:  NewLinkedHashMap[K, V](),
+		backward: NewLinkedHashMap[V, K](),
+	}
+	m.inverse = &adkBiMap[V, K]{
+		forward:  m.backward,
+		backward: m.forward,
+		inverse:  m,
+	}
+	return m
+}
+
+func (m *adkBiMap[K, V]) Put(key K, val V) error {
+	if k, ok := m.backward.Get(val); ok {
+		if k == key {
+			return nil
+		}
+		return ErrValueExists
+	}
+	m.put(key, val)
+	return nil
+}
+
+func (m *adkBiMap[K, V]) ForcePut(key K, val V) {
+	if k, ok := m.backward.Get(val); ok {
+		if k == key {
+			return
+		}
+		m.forward.Remove(k)
+	}
+	m.put(key, val)
+}
+
+func (m *adkBiMap[K, V]) put(key K, val V) {
+	if old, ok := m.forward.Get(key); ok {
+		m.backward.Remove(old)
+	}
+	m.forward.Put(key, val)
+	m.backward.Put(val, key)
+}
+
+func (m *adkBiMap[K, V]) Get(key K) (V, bool) {
+	return m.forward.Get(key)
+}
+
+func (m *adkBiMap[K, V]) GetKey(val V) (K, bool) {
+	return m.backward.Get(val)
+}
+
+func (m *adkBiMap[K, V]) Remove(key K) bool {
+	val, ok := m.forward.Get(key)
+	if !ok {
+		return false
+	}
+	m.forward.Remove(key)
+	m.backward.Remove(val)
+	return true
+}
+
+func (m *adkBiMap[K, V]) ContainsKey(key K) bool {
+	return m.forward.ContainsKey(key)
+}
+
+func (m *adkBiMap[K, V]) ContainsValue(val V) bool {
+	return m.backward.ContainsKey(val)
+}
+
+func (m *adkBiMap[K, V]) Len() int {
+	return m.forward.Len()
+}
+
+func (m *adkBiMap[K, V]) Keys() []K {
+	return m.forward.Keys()
+}
+
+func (m *adkBiMap[K, V]) Values() []V {
+	return m.forward.Values()
+}
+
+func (m *adkBiMap[K, V]) ForEach(fn func(key K, val V)) {
+	m.forward.ForEach(fn)
+}
+
+func (m *adkBiMap[K, V]) Clear() {
+	m.forward.Clear()
+	m.backward.Clear()
+}
+
+func (m *adkBiMap[K, V]) Inverse() BiMap[V, K] {
+	return m.inverse
+}
+
+func (m *adkBiMap[K, V]) MarshalJSON() ([]byte, error) {
+	return m.forward.MarshalJSON()
+}
+
+// UnmarshalJSON 用 JSON 对象中的键值对替换当前内容。
+// value 重复时返回 ErrValueExists，当前内容保持不变。
+func (m *adkBiMap[K, V]) UnmarshalJSON(data []byte) error {
+	forward := NewLinkedHashMap[K, V]()
+	if err := json.Unmarshal(data, forward); err != nil {
+		return err
+	}
+	backward := NewLinkedHashMap[V, K]()
+	var err error
+	forward.ForEach(func(key K, val V) {
+		if err == nil && backward.ContainsKey(val) {
+			err = ErrValueExists
+		}
+		backward.Put(val, key)
+	})
+	if err != nil {
+		return err
+	}
+	m.forward, m.backward = forward, backward
+	m.inverse.forward, m.inverse.backward = backward, forward
+	return nil
+}
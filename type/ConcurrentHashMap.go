@@ -0,0 +1,268 @@
+package gttype
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// ConcurrentHashMap 并发安全的 HashMap
+// key 按哈希值的高位分散到多个分片，每个分片持有一把读写锁，
+// 不同分片上的读写互不阻塞。
+type ConcurrentHashMap[K comparable, V any] interface {
+	HashMap[K, V]
+	// LoadOrStore key 存在时返回已有的值，否则写入 val
+	// loaded 表示值是否已经存在
+	LoadOrStore(key K, val V) (actual V, loaded bool)
+	// Compute 在持有分片锁的情况下原子地计算 key 的新值
+	// fn 的参数为旧值及其是否存在，返回新值以及是否保留，
+	// 不保留时删除 key。返回计算后的值及其是否存在。
+	Compute(key K, fn func(old V, exists bool) (V, bool)) (V, bool)
+	// ComputeIfAbsent key 不存在时原子地写入 fn 的返回值，并返回 key 当前的值
+	ComputeIfAbsent(key K, fn func() V) V
+	// Merge key 不存在时写入 val，否则写入 fn(旧值, val)，返回写入后的值
+	Merge(key K, val V, fn func(old, val V) V) V
+	// Range 弱一致性遍历，逐个分片取快照后回调，fn 返回 false 时停止
+	// 回调期间不持有锁，可以在 fn 中修改该 map
+	Range(fn func(key K, val V) bool)
+}
+
+// Scan 游标的高 16 位保存分片下标，低 48 位保存分片内的游标
+const (
+	shardCursorShift = 48
+	maxShards        = 1 << (64 - shardCursorShift)
+)
+
+type concurrentShard[K comparable, V any] struct {
+	sync.RWMutex
+	m *adkHashMap[K, V]
+}
+
+type adkConcurrentHashMap[K comparable, V any] struct {
+	shards []concurrentShard[K, V]
+	shift  uint // 哈希值右移 shift 位得到分片下标
+	hash   func(K) uint64
+}
+
+// NewConcurrentHashMap 创建一个分片加锁的并发 HashMap
+// 默认分片数为 CPU 核数的 4 倍
+func NewConcurrentHashMap[K comparable, V any](opts ...HashMapOption) ConcurrentHashMap[K, V] {
+	cfg := hashMapConfig{loadFactor: defaultLoadFactor, shards: runtime.GOMAXPROCS(0) * 4}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.shards > maxShards {
+		cfg.shards = maxShards
+	}
+	n := 1 << bits.Len(uint(cfg.shards-1))
+	shardCfg := cfg
+	shardCfg.capacity = cfg.capacity / n
+
+	hasher := ComparableHasher[K]{}
+	hash := seededHash[K](hasher, maphash.MakeSeed())
+	c := &adkConcurrentHashMap[K, V]{
+		shards: make([]concurrentShard[K, V], n),
+		shift:  uint(64 - bits.TrailingZeros(uint(n))),
+		hash:   hash,
+	}
+	for i := range c.shards {
+		c.shards[i].m = newAdkHashMap[K, V](hash, hasher.Equal, shardCfg)
+	}
+	return c
+}
+
+// shard 返回 key 所在的分片
+// 分片取哈希值的高位，表内寻址取低位，两者互不干扰
+func (c *adkConcurrentHashMap[K, V]) shard(key K) (*concurrentShard[K, V], uint64) {
+	hash := c.hash(key)
+	if len(c.shards) == 1 {
+		return &c.shards[0], hash
+	}
+	return &c.shards[hash>>c.shift], hash
+}
+
+func (c *adkConcurrentHashMap[K, V]) Put(key K, val V) {
+	s, hash := c.shard(key)
+	s.Lock()
+	s.m.put(hash, key, val)
+	s.Unlock()
+}
+
+func (c *adkConcurrentHashMap[K, V]) Get(key K) (V, bool) {
+	s, hash := c.shard(key)
+	s.RLock()
+	defer s.RUnlock()
+	return s.m.get(hash, key)
+}
+
+func (c *adkConcurrentHashMap[K, V]) Remove(key K) bool {
+	s, hash := c.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	return s.m.remove(hash, key)
+}
+
+func (c *adkConcurrentHashMap[K, V]) ContainsKey(key K) bool {
+	_, ok := c.Get(key)
+	return ok
+}
+
+// Len 返回各分片元素个数之和，并发写入时只是一个近似值
+func (c *adkConcurrentHashMap[K, V]) Len() int {
+	n := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.RLock()
+		n += s.m.Len()
+		s.RUnlock()
+	}
+	return n
+}
+
+func (c *adkConcurrentHashMap[K, V]) Clear() {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.Lock()
+		s.m.Clear()
+		s.Unlock()
+	}
+}
+
+func (c *adkConcurrentHashMap[K, V]) Keys() []K {
+	var keys []K
+	c.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func (c *adkConcurrentHashMap[K, V]) Values() []V {
+	var vals []V
+	c.Range(func(_ K, val V) bool {
+		vals = append(vals, val)
+		return true
+	})
+	return vals
+}
+
+func (c *adkConcurrentHashMap[K, V]) ForEach(fn func(key K, val V)) {
+	c.Range(func(key K, val V) bool {
+		fn(key, val)
+		return true
+	})
+}
+
+func (c *adkConcurrentHashMap[K, V]) LoadOrStore(key K, val V) (V, bool) {
+	s, hash := c.shard(key)
+	s.RLock()
+	actual, ok := s.m.get(hash, key)
+	s.RUnlock()
+	if ok {
+		return actual, true
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if actual, ok = s.m.get(hash, key); ok {
+		return actual, true
+	}
+	s.m.put(hash, key, val)
+	return val, false
+}
+
+func (c *adkConcurrentHashMap[K, V]) Compute(key K, fn func(old V, exists bool) (V, bool)) (V, bool) {
+	s, hash := c.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	old, exists := s.m.get(hash, key)
+	val, keep := fn(old, exists)
+	if !keep {
+		if exists {
+			s.m.remove(hash, key)
+		}
+		var zero V
+		return zero, false
+	}
+	s.m.put(hash, key, val)
+	return val, true
+}
+
+func (c *adkConcurrentHashMap[K, V]) ComputeIfAbsent(key K, fn func() V) V {
+	s, hash := c.shard(key)
+	s.RLock()
+	val, ok := s.m.get(hash, key)
+	s.RUnlock()
+	if ok {
+		return val
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if val, ok = s.m.get(hash, key); ok {
+		return val
+	}
+	val = fn()
+	s.m.put(hash, key, val)
+	return val
+}
+
+func (c *adkConcurrentHashMap[K, V]) Merge(key K, val V, fn func(old, val V) V) V {
+	s, hash := c.shard(key)
+	s.Lock()
+	defer s.Unlock()
+	if old, ok := s.m.get(hash, key); ok {
+		val = fn(old, val)
+	}
+	s.m.put(hash, key, val)
+	return val
+}
+
+func (c *adkConcurrentHashMap[K, V]) Range(fn func(key K, val V) bool) {
+	var keys []K
+	var vals []V
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.RLock()
+		keys = keys[:0]
+		vals = vals[:0]
+		s.m.forEach(func(key K, val V) bool {
+			keys = append(keys, key)
+			vals = append(vals, val)
+			return true
+		})
+		s.RUnlock()
+
+		for j := range keys {
+			if !fn(keys[j], vals[j]) {
+				return
+			}
+		}
+	}
+}
+
+// Scan 依次游标遍历每个分片，分片内的语义与 HashMap.Scan 相同
+func (c *adkConcurrentHashMap[K, V]) Scan(cursor uint64, count int) (uint64, []Entry[K, V]) {
+	if count <= 0 {
+		count = defaultScanCount
+	}
+	idx := cursor >> shardCursorShift
+	inner := cursor & (1<<shardCursorShift - 1)
+	var items []Entry[K, V]
+	for idx < uint64(len(c.shards)) && len(items) < count {
+		s := &c.shards[idx]
+		s.RLock()
+		inner = s.m.scan(inner, count-len(items), func(key K, val V) {
+			items = append(items, Entry[K, V]{Key: key, Value: val})
+		})
+		s.RUnlock()
+		if inner == 0 {
+			idx++
+		}
+	}
+	if idx >= uint64(len(c.shards)) {
+		return 0, items
+	}
+	return idx<<shardCursorShift | inner, items
+}
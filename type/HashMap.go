@@ -1,4 +1,393 @@
 package gttype
 
-type HashMap[K comparable, V any] interface {
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// HashMap 泛型哈希表
+// key 不可比较时使用 NewHashMapWith 传入自定义的 Hasher
+type HashMap[K, V any] interface {
+	Put(key K, val V)              // 写入键值对，已存在时覆盖
+	Get(key K) (V, bool)           // 获取 key 对应的值
+	Remove(key K) bool             // 删除 key，返回 key 是否存在
+	ContainsKey(key K) bool        // 判断 key 是否存在
+	Len() int                      // 元素个数
+	Clear()                        // 清空
+	Keys() []K                     // 所有的 key，顺序不固定
+	Values() []V                   // 所有的 value，顺序不固定
+	ForEach(fn func(key K, val V)) // 遍历所有键值对
+	// Scan 游标式遍历，语义与 Redis 的 SCAN 相同
+	// cursor 从 0 开始，返回的 next 为 0 时遍历结束，count 为每次返回元素个数的参考值。
+	// 遍历期间表扩容或缩容，始终存在的元素也至少会被返回一次，但可能重复返回。
+	Scan(cursor uint64, count int) (next uint64, items []Entry[K, V])
+}
+
+// Entry 键值对
+type Entry[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// HashMapOption HashMap 的可选配置
+type HashMapOption func(*hashMapConfig)
+
+type hashMapConfig struct {
+	loadFactor  float64
+	capacity    int
+	shards      int
+	rehashStep  int
+	accessOrder bool
+}
+
+// WithLoadFactor 设置装载因子，取值范围 (0, 1)，非法值使用默认的 0.875
+func WithLoadFactor(loadFactor float64) HashMapOption {
+	return func(c *hashMapConfig) {
+		if loadFactor > 0 && loadFactor < 1 {
+			c.loadFactor = loadFactor
+		}
+	}
+}
+
+// WithInitialCapacity 设置初始容量，避免写入时频繁扩容
+func WithInitialCapacity(capacity int) HashMapOption {
+	return func(c *hashMapConfig) {
+		if capacity > 0 {
+			c.capacity = capacity
+		}
+	}
+}
+
+// WithIncrementalRehash 开启渐进式 rehash（类似 Redis 的 dict）
+// 扩容时保留新旧两张表，之后的每次写操作最多迁移 groupsPerStep 个组（每组 8 个槽位），
+// 避免单次 Put 承担 O(n) 的扩容开销。读操作不会触发迁移。
+func WithIncrementalRehash(groupsPerStep int) HashMapOption {
+	return func(c *hashMapConfig) {
+		if groupsPerStep > 0 {
+			c.rehashStep = groupsPerStep
+		}
+	}
+}
+
+// WithShards 设置 ConcurrentHashMap 的分片数，会向上取整为 2 的幂
+func WithShards(shards int) HashMapOption {
+	return func(c *hashMapConfig) {
+		if shards > 0 {
+			c.shards = shards
+		}
+	}
+}
+
+// adkHashMap 基于 swissTable 的 HashMap 实现
+type adkHashMap[K, V any] struct {
+	table *swissTable[K, V]
+	// old 渐进式 rehash 过程中的旧表，未在 rehash 时为 nil
+	// 旧表中 rehashIdx 之前的组均已迁移到 table
+	old       *swissTable[K, V]
+	rehashIdx int
+	hash      func(K) uint64
+	equal     func(a, b K) bool
+	cfg       hashMapConfig
+}
+
+// NewHashMap 创建一个开放寻址的 HashMap
+func NewHashMap[K comparable, V any](opts ...HashMapOption) HashMap[K, V] {
+	return NewHashMapWith[K, V](ComparableHasher[K]{}, opts...)
+}
+
+// NewHashMapWith 使用自定义的 Hasher 创建 HashMap，key 可以是切片等不可比较类型
+func NewHashMapWith[K, V any](hasher Hasher[K], opts ...HashMapOption) HashMap[K, V] {
+	cfg := hashMapConfig{loadFactor: defaultLoadFactor}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return newAdkHashMap[K, V](seededHash(hasher, maphash.MakeSeed()), hasher.Equal, cfg)
+}
+
+func newAdkHashMap[K, V any](hash func(K) uint64, equal func(a, b K) bool, cfg hashMapConfig) *adkHashMap[K, V] {
+	return &adkHashMap[K, V]{
+		table: newSwissTable[K, V](groupsFor(cfg.capacity, cfg.loadFactor), cfg.loadFactor),
+		hash:  hash,
+		equal: equal,
+		cfg:   cfg,
+	}
+}
+
+// seededHash 绑定随机种子，同一个表内的哈希值保持一致
+func seededHash[K any](hasher Hasher[K], seed maphash.Seed) func(K) uint64 {
+	return func(key K) uint64 {
+		return hasher.Hash(seed, key)
+	}
+}
+
+func (m *adkHashMap[K, V]) Put(key K, val V) {
+	m.put(m.hash(key), key, val)
+}
+
+func (m *adkHashMap[K, V]) Get(key K) (V, bool) {
+	return m.get(m.hash(key), key)
+}
+
+func (m *adkHashMap[K, V]) Remove(key K) bool {
+	return m.remove(m.hash(key), key)
+}
+
+func (m *adkHashMap[K, V]) ContainsKey(key K) bool {
+	t, _, _ := m.find(m.hash(key), key)
+	return t != nil
+}
+
+// find 依次在旧表和新表中查找 key
+func (m *adkHashMap[K, V]) find(hash uint64, key K) (*swissTable[K, V], *swissGroup[K, V], int) {
+	if m.old != nil {
+		if g, i, ok := m.old.find(hash, key, m.equal); ok {
+			return m.old, g, i
+		}
+	}
+	if g, i, ok := m.table.find(hash, key, m.equal); ok {
+		return m.table, g, i
+	}
+	return nil, nil, 0
+}
+
+// put 使用已经计算好的哈希值写入
+func (m *adkHashMap[K, V]) put(hash uint64, key K, val V) {
+	if m.old != nil {
+		m.rehashStep()
+	}
+	if t, g, i := m.find(hash, key); t != nil {
+		g.vals[i] = val
+		return
+	}
+	if m.table.growthLeft == 0 {
+		m.grow()
+	}
+	m.table.insertNew(hash, key, val)
+}
+
+// get 使用已经计算好的哈希值查找
+func (m *adkHashMap[K, V]) get(hash uint64, key K) (V, bool) {
+	if t, g, i := m.find(hash, key); t != nil {
+		return g.vals[i], true
+	}
+	var zero V
+	return zero, false
+}
+
+// remove 使用已经计算好的哈希值删除
+func (m *adkHashMap[K, V]) remove(hash uint64, key K) bool {
+	if m.old != nil {
+		m.rehashStep()
+	}
+	t, g, i := m.find(hash, key)
+	if t == nil {
+		return false
+	}
+	t.removeAt(g, i)
+	m.shrink()
+	return true
+}
+
+func (m *adkHashMap[K, V]) Len() int {
+	if m.old != nil {
+		return m.table.used + m.old.used
+	}
+	return m.table.used
+}
+
+func (m *adkHashMap[K, V]) Clear() {
+	m.old = nil
+	m.rehashIdx = 0
+	m.table = newSwissTable[K, V](groupsFor(m.cfg.capacity, m.cfg.loadFactor), m.cfg.loadFactor)
+}
+
+func (m *adkHashMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	m.forEach(func(key K, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func (m *adkHashMap[K, V]) Values() []V {
+	vals := make([]V, 0, m.Len())
+	m.forEach(func(_ K, val V) bool {
+		vals = append(vals, val)
+		return true
+	})
+	return vals
+}
+
+func (m *adkHashMap[K, V]) ForEach(fn func(key K, val V)) {
+	m.forEach(func(key K, val V) bool {
+		fn(key, val)
+		return true
+	})
+}
+
+// forEach 遍历新旧两张表，fn 返回 false 时停止
+func (m *adkHashMap[K, V]) forEach(fn func(key K, val V) bool) bool {
+	if m.old != nil && !m.old.forEach(fn) {
+		return false
+	}
+	return m.table.forEach(fn)
+}
+
+// grow 没有空槽位可用时扩容
+// 未开启渐进式 rehash 时一次性重建；开启时创建新表并开始迁移
+func (m *adkHashMap[K, V]) grow() {
+	if m.old != nil {
+		// 上一轮迁移尚未完成新表就已写满，只能一次性重建
+		m.rehash()
+		return
+	}
+	m.resize(m.nextGroups(m.table))
+}
+
+// shrink 元素个数不超过容量的 1/8 时缩容，缩小到元素约占容量的 1/4，
+// 但不小于初始容量对应的组数。渐进式 rehash 期间不缩容
+func (m *adkHashMap[K, V]) shrink() {
+	if m.old != nil {
+		return
+	}
+	groups := len(m.table.groups)
+	minGroups := groupsFor(m.cfg.capacity, m.cfg.loadFactor)
+	used := m.table.used
+	if groups <= minGroups || used > maxLoad(groups, m.cfg.loadFactor)/8 {
+		return
+	}
+	for groups > minGroups && used <= maxLoad(groups/2, m.cfg.loadFactor)/4 {
+		groups /= 2
+	}
+	m.resize(groups)
+}
+
+// resize 把表重建为 groups 个组
+// 未开启渐进式 rehash 时一次性重建；开启时创建新表并开始迁移
+func (m *adkHashMap[K, V]) resize(groups int) {
+	if m.cfg.rehashStep == 0 {
+		m.rehashTo(groups)
+		return
+	}
+	m.old = m.table
+	m.rehashIdx = 0
+	m.table = newSwissTable[K, V](groups, m.cfg.loadFactor)
+	m.rehashStep()
+}
+
+// nextGroups 计算扩容后的组数
+// 墓碑较多时保持原大小以清理墓碑，否则扩容一倍
+func (m *adkHashMap[K, V]) nextGroups(t *swissTable[K, V]) int {
+	groups := len(t.groups)
+	if m.Len()+1 > maxLoad(groups, m.cfg.loadFactor)/2 {
+		groups *= 2
+	}
+	return groups
+}
+
+// rehash 一次性把所有元素迁移到扩容后的新表
+func (m *adkHashMap[K, V]) rehash() {
+	m.rehashTo(m.nextGroups(m.table))
+}
+
+// rehashTo 一次性把所有元素迁移到 groups 个组的新表
+func (m *adkHashMap[K, V]) rehashTo(groups int) {
+	t := newSwissTable[K, V](groups, m.cfg.loadFactor)
+	m.forEach(func(key K, val V) bool {
+		t.insertNew(m.hash(key), key, val)
+		return true
+	})
+	m.table = t
+	m.old = nil
+	m.rehashIdx = 0
+}
+
+// rehashStep 从旧表迁移至多 rehashStep 个组到新表
+// 已迁移的组原本没有空槽位时整体标记为墓碑，保证旧表中其余元素的探测序列不被截断；
+// 原本有空槽位时不会有探测序列越过该组，整体置空即可让查找尽早结束
+func (m *adkHashMap[K, V]) rehashStep() {
+	old := m.old
+	for n := 0; n < m.cfg.rehashStep && m.rehashIdx < len(old.groups); n++ {
+		if m.table.growthLeft < groupSlots {
+			// 新表容量不足以容纳一整组，直接完成剩余的迁移
+			m.rehash()
+			return
+		}
+		g := &old.groups[m.rehashIdx]
+		for s := g.ctrl.matchFull(); s != 0; s = s.next() {
+			i := s.first()
+			m.table.insertNew(m.hash(g.keys[i]), g.keys[i], g.vals[i])
+			old.used--
+		}
+		ctrl := ctrlWord(ctrlAllDeleted)
+		if g.ctrl.matchEmpty() != 0 {
+			ctrl = ctrlAllEmpty
+		}
+		*g = swissGroup[K, V]{ctrl: ctrl}
+		m.rehashIdx++
+	}
+	if m.rehashIdx == len(old.groups) {
+		m.old = nil
+		m.rehashIdx = 0
+	}
+}
+
+// defaultScanCount Scan 时 count 不大于 0 使用的默认值
+const defaultScanCount = 10
+
+func (m *adkHashMap[K, V]) Scan(cursor uint64, count int) (uint64, []Entry[K, V]) {
+	var items []Entry[K, V]
+	next := m.scan(cursor, count, func(key K, val V) {
+		items = append(items, Entry[K, V]{Key: key, Value: val})
+	})
+	return next, items
+}
+
+// scan 采用与 Redis dictScan 相同的反向二进制游标：
+// 游标的低位对应组下标，每次从最高位开始进位。表大小是 2 的幂，
+// 扩容后一个组被拆成若干高位不同的组，缩容时则合并，
+// 反向递增保证已经遍历过的组在新大小下对应的组都排在游标之前。
+// 渐进式 rehash 期间先遍历小表中的组，再遍历大表中由它展开的所有组。
+func (m *adkHashMap[K, V]) scan(cursor uint64, count int, fn func(key K, val V)) uint64 {
+	if count <= 0 {
+		count = defaultScanCount
+	}
+	n := 0
+	emit := func(key K, val V) {
+		n++
+		fn(key, val)
+	}
+	for {
+		if m.old == nil {
+			t := m.table
+			t.scanHome(cursor&t.mask, m.hash, emit)
+			cursor = nextScanCursor(cursor, t.mask)
+		} else {
+			small, large := m.old, m.table
+			if len(small.groups) > len(large.groups) {
+				small, large = large, small
+			}
+			m0, m1 := small.mask, large.mask
+			small.scanHome(cursor&m0, m.hash, emit)
+			for {
+				large.scanHome(cursor&m1, m.hash, emit)
+				cursor = nextScanCursor(cursor, m1)
+				if cursor&(m0^m1) == 0 {
+					break
+				}
+			}
+		}
+		if cursor == 0 || n >= count {
+			return cursor
+		}
+	}
+}
+
+// nextScanCursor 对 mask 覆盖的位做反向二进制加一
+func nextScanCursor(cursor, mask uint64) uint64 {
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+	return bits.Reverse64(cursor)
 }
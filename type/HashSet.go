@@ -1,54 +1,171 @@
 package gttype
 
+import "hash/maphash"
+
+// HashSet 泛型集合
+// NewHashSet 要求元素可比较，在编译期保证类型安全；
+// 切片等不可比较的元素通过 NewHashSetWith 传入 Hasher，因此接口本身使用 any 约束
 type HashSet[T any] interface {
 	Add(T) HashSet[T]
 	Size() int
-	Remove(T) bool
+	Remove(T) bool // 删除元素，返回元素是否存在
 	IsEmpty() bool
 	Clear()
 	GetData() []T
+	// Scan 游标式遍历，语义与 HashMap.Scan 相同，适合分批遍历大集合
+	Scan(cursor uint64, count int) (next uint64, items []T)
+	Contains(T) bool
+	ForEach(fn func(T))
+
+	// 集合运算均返回新的集合，不修改参与运算的集合
+	// 新集合使用接收者的 Hasher
+	Union(other HashSet[T]) HashSet[T]               // 并集
+	Intersect(other HashSet[T]) HashSet[T]           // 交集
+	Difference(other HashSet[T]) HashSet[T]          // 差集，属于当前集合但不属于 other
+	SymmetricDifference(other HashSet[T]) HashSet[T] // 对称差集，只属于其中一个集合
+	IsSubsetOf(other HashSet[T]) bool                // 是否为 other 的子集
+	IsSupersetOf(other HashSet[T]) bool              // 是否为 other 的超集
+	IsDisjoint(other HashSet[T]) bool                // 是否没有公共元素
+	Equal(other HashSet[T]) bool                     // 元素是否完全相同
 }
 
 type adkHashSet[T any] struct {
-	Data map[any]struct{}
+	data *adkHashMap[T, struct{}]
 }
 
 func (a *adkHashSet[T]) GetData() []T {
-	var data []T
-	for k := range a.Data {
-		data = append(data, k.(T))
-	}
-	return data
+	return a.data.Keys()
 }
 
 func (a *adkHashSet[T]) Add(val T) HashSet[T] {
-	a.Data[val] = struct{}{}
+	a.data.Put(val, struct{}{})
 	return a
 }
 
 func (a *adkHashSet[T]) Size() int {
-	return len(a.Data)
+	return a.data.Len()
 }
 
 func (a *adkHashSet[T]) Remove(val T) bool {
-	delete(a.Data, val)
-	return true
+	return a.data.Remove(val)
 }
 
 func (a *adkHashSet[T]) IsEmpty() bool {
-	//TODO implement me
-	if a.Size() == 0 {
+	return a.Size() == 0
+}
+
+func (a *adkHashSet[T]) Clear() {
+	a.data.Clear()
+}
+
+func (a *adkHashSet[T]) Scan(cursor uint64, count int) (uint64, []T) {
+	var items []T
+	next := a.data.scan(cursor, count, func(key T, _ struct{}) {
+		items = append(items, key)
+	})
+	return next, items
+}
+
+func (a *adkHashSet[T]) Contains(val T) bool {
+	return a.data.ContainsKey(val)
+}
+
+func (a *adkHashSet[T]) ForEach(fn func(T)) {
+	a.data.forEach(func(key T, _ struct{}) bool {
+		fn(key)
+		return true
+	})
+}
+
+func (a *adkHashSet[T]) Union(other HashSet[T]) HashSet[T] {
+	res := a.clone()
+	other.ForEach(func(val T) {
+		res.Add(val)
+	})
+	return res
+}
+
+func (a *adkHashSet[T]) Intersect(other HashSet[T]) HashSet[T] {
+	res := a.empty()
+	a.data.forEach(func(key T, _ struct{}) bool {
+		if other.Contains(key) {
+			res.Add(key)
+		}
+		return true
+	})
+	return res
+}
+
+func (a *adkHashSet[T]) Difference(other HashSet[T]) HashSet[T] {
+	res := a.empty()
+	a.data.forEach(func(key T, _ struct{}) bool {
+		if !other.Contains(key) {
+			res.Add(key)
+		}
 		return true
+	})
+	return res
+}
+
+func (a *adkHashSet[T]) SymmetricDifference(other HashSet[T]) HashSet[T] {
+	res := a.Difference(other)
+	other.ForEach(func(val T) {
+		if !a.Contains(val) {
+			res.Add(val)
+		}
+	})
+	return res
+}
+
+func (a *adkHashSet[T]) IsSubsetOf(other HashSet[T]) bool {
+	if a.Size() > other.Size() {
+		return false
 	}
-	return false
+	return a.data.forEach(func(key T, _ struct{}) bool {
+		return other.Contains(key)
+	})
 }
 
-func (a *adkHashSet[T]) Clear() {
-	a.Data = make(map[any]struct{})
+func (a *adkHashSet[T]) IsSupersetOf(other HashSet[T]) bool {
+	return other.IsSubsetOf(a)
+}
+
+func (a *adkHashSet[T]) IsDisjoint(other HashSet[T]) bool {
+	return a.data.forEach(func(key T, _ struct{}) bool {
+		return !other.Contains(key)
+	})
+}
+
+func (a *adkHashSet[T]) Equal(other HashSet[T]) bool {
+	return a.Size() == other.Size() && a.IsSubsetOf(other)
+}
+
+// empty 创建一个与当前集合使用相同 Hasher 的空集合
+func (a *adkHashSet[T]) empty() *adkHashSet[T] {
+	return &adkHashSet[T]{
+		data: newAdkHashMap[T, struct{}](a.data.hash, a.data.equal, hashMapConfig{loadFactor: defaultLoadFactor}),
+	}
+}
+
+// clone 复制当前集合
+func (a *adkHashSet[T]) clone() *adkHashSet[T] {
+	res := a.empty()
+	a.data.forEach(func(key T, _ struct{}) bool {
+		res.Add(key)
+		return true
+	})
+	return res
+}
+
+// NewHashSet 创建元素可比较的 HashSet
+// 元素为切片或含不可比较字段的结构体时使用 NewHashSetWith
+func NewHashSet[T comparable]() HashSet[T] {
+	return NewHashSetWith[T](ComparableHasher[T]{})
 }
 
-func NewHashSet[T any]() HashSet[T] {
+// NewHashSetWith 使用自定义的 Hasher 创建 HashSet
+func NewHashSetWith[T any](hasher Hasher[T]) HashSet[T] {
 	return &adkHashSet[T]{
-		Data: make(map[any]struct{}),
+		data: newAdkHashMap[T, struct{}](seededHash(hasher, maphash.MakeSeed()), hasher.Equal, hashMapConfig{loadFactor: defaultLoadFactor}),
 	}
 }
@@ -0,0 +1,145 @@
+package gttype
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Hasher 自定义 HashMap/HashSet 中 key 的哈希与相等判断
+// 必须保证 Equal(a, b) 为 true 时 Hash(seed, a) == Hash(seed, b)
+type Hasher[T any] interface {
+	Hash(seed maphash.Seed, val T) uint64
+	Equal(a, b T) bool
+}
+
+// ComparableHasher 可比较类型的默认 Hasher
+type ComparableHasher[T comparable] struct{}
+
+func (ComparableHasher[T]) Hash(seed maphash.Seed, val T) uint64 {
+	return maphash.Comparable(seed, val)
+}
+
+func (ComparableHasher[T]) Equal(a, b T) bool {
+	return a == b
+}
+
+// BytesHasher 按内容比较 []byte
+type BytesHasher struct{}
+
+func (BytesHasher) Hash(seed maphash.Seed, val []byte) uint64 {
+	return maphash.Bytes(seed, val)
+}
+
+func (BytesHasher) Equal(a, b []byte) bool {
+	return bytes.Equal(a, b)
+}
+
+// SliceHasher 按元素逐个比较元素可比较的切片
+type SliceHasher[T comparable] struct{}
+
+func (SliceHasher[T]) Hash(seed maphash.Seed, val []T) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	for _, v := range val {
+		maphash.WriteComparable(&h, v)
+	}
+	return h.Sum64()
+}
+
+func (SliceHasher[T]) Equal(a, b []T) bool {
+	return slices.Equal(a, b)
+}
+
+// StructHasher 基于反射的结构化 Hasher，适用于含切片、map 等不可比较字段的结构体
+// 相等判断使用 reflect.DeepEqual，哈希值按相同的规则递归计算所有字段（包括未导出字段）
+type StructHasher[T any] struct{}
+
+func (StructHasher[T]) Hash(seed maphash.Seed, val T) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	writeValue(&h, seed, reflect.ValueOf(&val).Elem(), 0)
+	return h.Sum64()
+}
+
+func (StructHasher[T]) Equal(a, b T) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// maxHashDepth 指针递归的最大深度，防止循环引用导致无限递归
+const maxHashDepth = 32
+
+// writeValue 把 v 的内容写入 h，规则与 reflect.DeepEqual 一致：
+// 指针与接口比较指向的值，map 与遍历顺序无关，浮点数 -0 与 0 相等
+func writeValue(h *maphash.Hash, seed maphash.Seed, v reflect.Value, depth int) {
+	var buf [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		h.Write(buf[:])
+	}
+	writeFloat := func(f float64) {
+		if f == 0 {
+			f = 0
+		}
+		writeUint(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(real(c))
+		writeFloat(imag(c))
+	case reflect.String:
+		h.WriteString(v.String())
+		h.WriteByte(0)
+	case reflect.Slice, reflect.Array:
+		writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			writeValue(h, seed, v.Index(i), depth)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeValue(h, seed, v.Field(i), depth)
+		}
+	case reflect.Map:
+		// 每个键值对单独计算哈希后相加，与遍历顺序无关
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			var eh maphash.Hash
+			eh.SetSeed(seed)
+			writeValue(&eh, seed, iter.Key(), depth)
+			writeValue(&eh, seed, iter.Value(), depth)
+			sum += eh.Sum64()
+		}
+		writeUint(uint64(v.Len()))
+		writeUint(sum)
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		h.WriteByte(1)
+		if depth < maxHashDepth {
+			writeValue(h, seed, v.Elem(), depth+1)
+		}
+	default:
+		// chan、func 等只有同为 nil 或同一对象时才相等，哈希值不区分
+		h.WriteByte(byte(v.Kind()))
+	}
+}
@@ -0,0 +1,309 @@
+package gttype
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"hash/maphash"
+)
+
+// LinkedHashMap 按插入顺序或访问顺序遍历的 HashMap
+// Keys、Values、ForEach 以及 JSON 序列化都按链表顺序进行，保证输出稳定。
+// 访问顺序模式下 Get 和 Put 会把元素移到末尾，结合 SetRemoveEldest 即可实现 LRU。
+type LinkedHashMap[K, V any] interface {
+	HashMap[K, V]
+	json.Marshaler
+	json.Unmarshaler
+	// Peek 获取 key 对应的值，不改变访问顺序
+	Peek(key K) (V, bool)
+	// Eldest 返回链表头部（最早插入或最久未访问）的元素
+	Eldest() (key K, val V, ok bool)
+	// Newest 返回链表尾部（最近插入或最近访问）的元素
+	Newest() (key K, val V, ok bool)
+	// RemoveEldest 删除并返回链表头部的元素
+	RemoveEldest() (key K, val V, ok bool)
+	// SetRemoveEldest 设置写入新元素后的回调，参数为当前最老的元素，
+	// 返回 true 时删除该元素
+	SetRemoveEldest(fn func(key K, val V) bool)
+}
+
+// WithAccessOrder LinkedHashMap 按访问顺序排列，默认按插入顺序
+func WithAccessOrder() HashMapOption {
+	return func(c *hashMapConfig) {
+		c.accessOrder = true
+	}
+}
+
+type linkedEntry[K, V any] struct {
+	key K
+	val V
+}
+
+// adkLinkedHashMap 哈希表保存 key 到链表节点的映射
+// 链表与 adkLinkList 一样使用带哨兵的双向循环链表
+type adkLinkedHashMap[K, V any] struct {
+	m            *adkHashMap[K, *node[linkedEntry[K, V]]]
+	head         *node[linkedEntry[K, V]]
+	accessOrder  bool
+	removeEldest func(key K, val V) bool
+}
+
+// NewLinkedHashMap 创建一个有序的 HashMap
+func NewLinkedHashMap[K comparable, V any](opts ...HashMapOption) LinkedHashMap[K, V] {
+	return NewLinkedHashMapWith[K, V](ComparableHasher[K]{}, opts...)
+}
+
+// NewLinkedHashMapWith 使用自定义的 Hasher 创建 LinkedHashMap
+func NewLinkedHashMapWith[K, V any](hasher Hasher[K], opts ...HashMapOption) LinkedHashMap[K, V] {
+	cfg := hashMapConfig{loadFactor: defaultLoadFactor}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	head := new(node[linkedEntry[K, V]])
+	head.aft = head
+	head.pri = head
+	return &adkLinkedHashMap[K, V]{
+		m:           newAdkHashMap[K, *node[linkedEntry[K, V]]](seededHash(hasher, maphash.MakeSeed()), hasher.Equal, cfg),
+		head:        head,
+		accessOrder: cfg.accessOrder,
+	}
+}
+
+// unlink 把节点从链表中摘除
+func (l *adkLinkedHashMap[K, V]) unlink(n *node[linkedEntry[K, V]]) {
+	n.pri.aft = n.aft
+	n.aft.pri = n.pri
+	n.pri = nil
+	n.aft = nil
+}
+
+// linkLast 把节点接到链表末尾
+func (l *adkLinkedHashMap[K, V]) linkLast(n *node[linkedEntry[K, V]]) {
+	n.pri = l.head.pri
+	n.aft = l.head
+	l.head.pri.aft = n
+	l.head.pri = n
+}
+
+func (l *adkLinkedHashMap[K, V]) moveToLast(n *node[linkedEntry[K, V]]) {
+	if l.head.pri == n {
+		return
+	}
+	l.unlink(n)
+	l.linkLast(n)
+}
+
+func (l *adkLinkedHashMap[K, V]) Put(key K, val V) {
+	hash := l.m.hash(key)
+	if n, ok := l.m.get(hash, key); ok {
+		n.val.val = val
+		if l.accessOrder {
+			l.moveToLast(n)
+		}
+		return
+	}
+	n := &node[linkedEntry[K, V]]{val: linkedEntry[K, V]{key: key, val: val}}
+	l.m.put(hash, key, n)
+	l.linkLast(n)
+
+	if l.removeEldest != nil {
+		eldest := l.head.aft
+		if l.removeEldest(eldest.val.key, eldest.val.val) {
+			l.m.Remove(eldest.val.key)
+			l.unlink(eldest)
+		}
+	}
+}
+
+func (l *adkLinkedHashMap[K, V]) Get(key K) (V, bool) {
+	n, ok := l.m.Get(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if l.accessOrder {
+		l.moveToLast(n)
+	}
+	return n.val.val, true
+}
+
+func (l *adkLinkedHashMap[K, V]) Peek(key K) (V, bool) {
+	n, ok := l.m.Get(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return n.val.val, true
+}
+
+func (l *adkLinkedHashMap[K, V]) Remove(key K) bool {
+	hash := l.m.hash(key)
+	n, ok := l.m.get(hash, key)
+	if !ok {
+		return false
+	}
+	l.m.remove(hash, key)
+	l.unlink(n)
+	return true
+}
+
+func (l *adkLinkedHashMap[K, V]) ContainsKey(key K) bool {
+	return l.m.ContainsKey(key)
+}
+
+func (l *adkLinkedHashMap[K, V]) Len() int {
+	return l.m.Len()
+}
+
+func (l *adkLinkedHashMap[K, V]) Clear() {
+	l.m.Clear()
+	l.head.aft = l.head
+	l.head.pri = l.head
+}
+
+func (l *adkLinkedHashMap[K, V]) Keys() []K {
+	keys := make([]K, 0, l.Len())
+	for n := l.head.aft; n != l.head; n = n.aft {
+		keys = append(keys, n.val.key)
+	}
+	return keys
+}
+
+func (l *adkLinkedHashMap[K, V]) Values() []V {
+	vals := make([]V, 0, l.Len())
+	for n := l.head.aft; n != l.head; n = n.aft {
+		vals = append(vals, n.val.val)
+	}
+	return vals
+}
+
+func (l *adkLinkedHashMap[K, V]) ForEach(fn func(key K, val V)) {
+	for n := l.head.aft; n != l.head; {
+		// 先取后继，允许在 fn 中删除当前元素
+		next := n.aft
+		fn(n.val.key, n.val.val)
+		n = next
+	}
+}
+
+// Scan 按哈希表的游标遍历，不保证链表顺序
+func (l *adkLinkedHashMap[K, V]) Scan(cursor uint64, count int) (uint64, []Entry[K, V]) {
+	var items []Entry[K, V]
+	next := l.m.scan(cursor, count, func(key K, n *node[linkedEntry[K, V]]) {
+		items = append(items, Entry[K, V]{Key: key, Value: n.val.val})
+	})
+	return next, items
+}
+
+func (l *adkLinkedHashMap[K, V]) Eldest() (K, V, bool) {
+	return l.entryOf(l.head.aft)
+}
+
+func (l *adkLinkedHashMap[K, V]) Newest() (K, V, bool) {
+	return l.entryOf(l.head.pri)
+}
+
+func (l *adkLinkedHashMap[K, V]) RemoveEldest() (K, V, bool) {
+	key, val, ok := l.entryOf(l.head.aft)
+	if ok {
+		l.Remove(key)
+	}
+	return key, val, ok
+}
+
+func (l *adkLinkedHashMap[K, V]) SetRemoveEldest(fn func(key K, val V) bool) {
+	l.removeEldest = fn
+}
+
+func (l *adkLinkedHashMap[K, V]) entryOf(n *node[linkedEntry[K, V]]) (K, V, bool) {
+	if n == l.head {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return n.val.key, n.val.val, true
+}
+
+// MarshalJSON 按链表顺序输出 JSON 对象
+// key 序列化后为字符串时直接作为对象的键，为数字、布尔值时加上引号
+func (l *adkLinkedHashMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n := l.head.aft; n != l.head; n = n.aft {
+		if n != l.head.aft {
+			buf.WriteByte(',')
+		}
+		key, err := marshalJSONKey(n.val.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(n.val.val)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 按 JSON 对象中键的顺序依次写入
+func (l *adkLinkedHashMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return errors.New("LinkedHashMap: JSON 数据必须是对象")
+	}
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := unmarshalJSONKey[K](tok.(string))
+		if err != nil {
+			return err
+		}
+		var val V
+		if err = dec.Decode(&val); err != nil {
+			return err
+		}
+		l.Put(key, val)
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func marshalJSONKey[K any](key K) ([]byte, error) {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("LinkedHashMap: 无法序列化 key")
+	}
+	switch data[0] {
+	case '"':
+		return data, nil
+	case '{', '[':
+		return nil, errors.New("LinkedHashMap: key 只能序列化为字符串、数字或布尔值")
+	}
+	return json.Marshal(string(data))
+}
+
+func unmarshalJSONKey[K any](s string) (K, error) {
+	var key K
+	quoted, _ := json.Marshal(s)
+	if err := json.Unmarshal(quoted, &key); err == nil {
+		return key, nil
+	}
+	err := json.Unmarshal([]byte(s), &key)
+	return key, err
+}
@@ -0,0 +1,251 @@
+package gttype
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MultiMap 一个 key 对应多个 value 的 map
+// key 按首次写入的顺序遍历，同一个 key 下的 value 也按写入顺序排列。
+type MultiMap[K, V comparable] interface {
+	json.Marshaler
+	json.Unmarshaler
+	// Put 为 key 添加一个 value，返回是否添加成功
+	// 集合桶中 value 已存在时返回 false
+	Put(key K, val V) bool
+	// GetAll 返回 key 对应的所有 value
+	GetAll(key K) []V
+	// RemoveValue 删除 key 下的一个 val，key 下没有 value 时同时删除 key
+	RemoveValue(key K, val V) bool
+	// RemoveAll 删除 key 及其所有 value，返回被删除的 value
+	RemoveAll(key K) []V
+	ContainsKey(key K) bool
+	ContainsEntry(key K, val V) bool
+	KeyCount() int   // key 的个数
+	ValueCount() int // 所有 value 的个数
+	Keys() []K
+	ForEach(fn func(key K, val V))
+	Clear()
+}
+
+// multiBucket 一个 key 下的所有 value
+type multiBucket[V comparable] interface {
+	add(val V) bool
+	remove(val V) bool
+	contains(val V) bool
+	values() []V
+	len() int
+}
+
+// listBucket 允许重复 value 的列表桶
+type listBucket[V comparable] struct {
+	vals []V
+}
+
+func (b *listBucket[V]) add(val V) bool {
+	b.vals = append(b.vals, val)
+	return true
+}
+
+func (b *listBucket[V]) remove(val V) bool {
+	for i, v := range b.vals {
+		if v == val {
+			b.vals = append(b.vals[:i], b.vals[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (b *listBucket[V]) contains(val V) bool {
+	for _, v := range b.vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *listBucket[V]) values() []V {
+	return append([]V(nil), b.vals...)
+}
+
+func (b *listBucket[V]) len() int {
+	return len(b.vals)
+}
+
+// setBucket value 去重的集合桶，保留写入顺序
+type setBucket[V comparable] struct {
+	vals LinkedHashMap[V, struct{}]
+}
+
+func (b *setBucket[V]) add(val V) bool {
+	if b.vals.ContainsKey(val) {
+		return false
+	}
+	b.vals.Put(val, struct{}{})
+	return true
+}
+
+func (b *setBucket[V]) remove(val V) bool {
+	return b.vals.Remove(val)
+}
+
+func (b *setBucket[V]) contains(val V) bool {
+	return b.vals.ContainsKey(val)
+}
+
+func (b *setBucket[V]) values() []V {
+	return b.vals.Keys()
+}
+
+func (b *setBucket[V]) len() int {
+	return b.vals.Len()
+}
+
+type adkMultiMap[K, V comparable] struct {
+	buckets   LinkedHashMap[K, multiBucket[V]]
+	newBucket func() multiBucket[V]
+	size      int
+}
+
+// NewListMultiMap 创建一个 value 可重复的 MultiMap
+func NewListMultiMap[K, V comparable]() MultiMap[K, V] {
+	return &adkMultiMap[K, V]{
+		buckets: NewLinkedHashMap[K, multiBucket[V]](),
+		newBucket: func() multiBucket[V] {
+			return &listBucket[V]{}
+		},
+	}
+}
+
+// NewSetMultiMap 创建一个同一 key 下 value 不重复的 MultiMap
+func NewSetMultiMap[K, V comparable]() MultiMap[K, V] {
+	return &adkMultiMap[K, V]{
+		buckets: NewLinkedHashMap[K, multiBucket[V]](),
+		newBucket: func() multiBucket[V] {
+			return &setBucket[V]{vals: NewLinkedHashMap[V, struct{}]()}
+		},
+	}
+}
+
+func (m *adkMultiMap[K, V]) Put(key K, val V) bool {
+	b, ok := m.buckets.Get(key)
+	if !ok {
+		b = m.newBucket()
+		m.buckets.Put(key, b)
+	}
+	if !b.add(val) {
+		return false
+	}
+	m.size++
+	return true
+}
+
+func (m *adkMultiMap[K, V]) GetAll(key K) []V {
+	if b, ok := m.buckets.Get(key); ok {
+		return b.values()
+	}
+	return nil
+}
+
+func (m *adkMultiMap[K, V]) RemoveValue(key K, val V) bool {
+	b, ok := m.buckets.Get(key)
+	if !ok || !b.remove(val) {
+		return false
+	}
+	m.size--
+	if b.len() == 0 {
+		m.buckets.Remove(key)
+	}
+	return true
+}
+
+func (m *adkMultiMap[K, V]) RemoveAll(key K) []V {
+	b, ok := m.buckets.Get(key)
+	if !ok {
+		return nil
+	}
+	m.buckets.Remove(key)
+	m.size -= b.len()
+	return b.values()
+}
+
+func (m *adkMultiMap[K, V]) ContainsKey(key K) bool {
+	return m.buckets.ContainsKey(key)
+}
+
+func (m *adkMultiMap[K, V]) ContainsEntry(key K, val V) bool {
+	b, ok := m.buckets.Get(key)
+	return ok && b.contains(val)
+}
+
+func (m *adkMultiMap[K, V]) KeyCount() int {
+	return m.buckets.Len()
+}
+
+func (m *adkMultiMap[K, V]) ValueCount() int {
+	return m.size
+}
+
+func (m *adkMultiMap[K, V]) Keys() []K {
+	return m.buckets.Keys()
+}
+
+func (m *adkMultiMap[K, V]) ForEach(fn func(key K, val V)) {
+	m.buckets.ForEach(func(key K, b multiBucket[V]) {
+		for _, v := range b.values() {
+			fn(key, v)
+		}
+	})
+}
+
+func (m *adkMultiMap[K, V]) Clear() {
+	m.buckets.Clear()
+	m.size = 0
+}
+
+// MarshalJSON 序列化为 key 到 value 数组的 JSON 对象
+func (m *adkMultiMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	var err error
+	first := true
+	m.buckets.ForEach(func(key K, b multiBucket[V]) {
+		if err != nil {
+			return
+		}
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		var data []byte
+		if data, err = marshalJSONKey(key); err != nil {
+			return
+		}
+		buf.Write(data)
+		buf.WriteByte(':')
+		if data, err = json.Marshal(b.values()); err != nil {
+			return
+		}
+		buf.Write(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (m *adkMultiMap[K, V]) UnmarshalJSON(data []byte) error {
+	entries := NewLinkedHashMap[K, []V]()
+	if err := json.Unmarshal(data, entries); err != nil {
+		return err
+	}
+	entries.ForEach(func(key K, vals []V) {
+		for _, v := range vals {
+			m.Put(key, v)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,340 @@
+package gttype
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// PersistentMap 基于哈希数组映射前缀树（HAMT）的不可变 map
+// Set、Delete 不修改当前版本，而是返回新版本，新旧版本共享未变化的节点。
+// 任何版本都不会再被修改，可以不加锁地交给多个 goroutine 读取。
+type PersistentMap[K, V any] interface {
+	Get(key K) (V, bool)
+	ContainsKey(key K) bool
+	Len() int
+	// Set 返回写入 key 后的新版本
+	Set(key K, val V) PersistentMap[K, V]
+	// Delete 返回删除 key 后的新版本，key 不存在时返回当前版本
+	Delete(key K) PersistentMap[K, V]
+	Keys() []K
+	Values() []V
+	ForEach(fn func(key K, val V))
+	// Builder 基于当前版本创建一个可变的构建器，适合批量写入
+	Builder() PersistentMapBuilder[K, V]
+}
+
+// PersistentMapBuilder PersistentMap 的可变构建器（transient）
+// 构建器创建的节点只属于它自己，可以原地修改，避免每次写入都复制路径上的节点。
+// 构建器不是并发安全的。
+type PersistentMapBuilder[K, V any] interface {
+	Get(key K) (V, bool)
+	Len() int
+	Set(key K, val V)
+	Delete(key K)
+	// Build 返回当前内容的不可变版本，之后构建器仍可继续使用，
+	// 但不会再修改已经返回的版本
+	Build() PersistentMap[K, V]
+}
+
+const (
+	hamtBits  = 5
+	hamtWidth = 1 << hamtBits
+	hamtMask  = hamtWidth - 1
+)
+
+// hamtEdit 构建器的所有权标记，节点的 edit 与构建器相同时才允许原地修改
+type hamtEdit struct{ _ int }
+
+type hamtEntry[K, V any] struct {
+	child *hamtNode[K, V] // 不为 nil 时表示子节点，否则为键值对
+	hash  uint64
+	key   K
+	val   V
+}
+
+// hamtNode 用 bitmap 标记 32 个分支中存在的分支，entries 只保存存在的分支
+// 哈希值的 64 位用完后（shift >= 64）节点退化为冲突链表，entries 按顺序保存冲突的 key
+type hamtNode[K, V any] struct {
+	bitmap  uint32
+	entries []hamtEntry[K, V]
+	edit    *hamtEdit
+}
+
+type hamtContext[K, V any] struct {
+	hash  func(K) uint64
+	equal func(a, b K) bool
+}
+
+type adkPersistentMap[K, V any] struct {
+	ctx   *hamtContext[K, V]
+	root  *hamtNode[K, V]
+	count int
+}
+
+// NewPersistentMap 创建一个空的 PersistentMap
+func NewPersistentMap[K comparable, V any]() PersistentMap[K, V] {
+	return NewPersistentMapWith[K, V](ComparableHasher[K]{})
+}
+
+// NewPersistentMapWith 使用自定义的 Hasher 创建 PersistentMap
+func NewPersistentMapWith[K, V any](hasher Hasher[K]) PersistentMap[K, V] {
+	return &adkPersistentMap[K, V]{
+		ctx: &hamtContext[K, V]{
+			hash:  seededHash(hasher, maphash.MakeSeed()),
+			equal: hasher.Equal,
+		},
+		root: &hamtNode[K, V]{},
+	}
+}
+
+func (p *adkPersistentMap[K, V]) Get(key K) (V, bool) {
+	return p.ctx.get(p.root, key)
+}
+
+func (p *adkPersistentMap[K, V]) ContainsKey(key K) bool {
+	_, ok := p.Get(key)
+	return ok
+}
+
+func (p *adkPersistentMap[K, V]) Len() int {
+	return p.count
+}
+
+func (p *adkPersistentMap[K, V]) Set(key K, val V) PersistentMap[K, V] {
+	root, added := p.ctx.set(p.root, 0, p.ctx.hash(key), key, val, nil)
+	count := p.count
+	if added {
+		count++
+	}
+	return &adkPersistentMap[K, V]{ctx: p.ctx, root: root, count: count}
+}
+
+func (p *adkPersistentMap[K, V]) Delete(key K) PersistentMap[K, V] {
+	root, removed := p.ctx.delete(p.root, 0, p.ctx.hash(key), key, nil)
+	if !removed {
+		return p
+	}
+	return &adkPersistentMap[K, V]{ctx: p.ctx, root: root, count: p.count - 1}
+}
+
+func (p *adkPersistentMap[K, V]) Keys() []K {
+	keys := make([]K, 0, p.count)
+	p.ForEach(func(key K, _ V) {
+		keys = append(keys, key)
+	})
+	return keys
+}
+
+func (p *adkPersistentMap[K, V]) Values() []V {
+	vals := make([]V, 0, p.count)
+	p.ForEach(func(_ K, val V) {
+		vals = append(vals, val)
+	})
+	return vals
+}
+
+func (p *adkPersistentMap[K, V]) ForEach(fn func(key K, val V)) {
+	p.root.forEach(fn)
+}
+
+func (p *adkPersistentMap[K, V]) Builder() PersistentMapBuilder[K, V] {
+	return &adkPersistentMapBuilder[K, V]{
+		ctx:   p.ctx,
+		root:  p.root,
+		count: p.count,
+		edit:  new(hamtEdit),
+	}
+}
+
+type adkPersistentMapBuilder[K, V any] struct {
+	ctx   *hamtContext[K, V]
+	root  *hamtNode[K, V]
+	count int
+	edit  *hamtEdit
+}
+
+func (b *adkPersistentMapBuilder[K, V]) Get(key K) (V, bool) {
+	return b.ctx.get(b.root, key)
+}
+
+func (b *adkPersistentMapBuilder[K, V]) Len() int {
+	return b.count
+}
+
+func (b *adkPersistentMapBuilder[K, V]) Set(key K, val V) {
+	root, added := b.ctx.set(b.root, 0, b.ctx.hash(key), key, val, b.edit)
+	b.root = root
+	if added {
+		b.count++
+	}
+}
+
+func (b *adkPersistentMapBuilder[K, V]) Delete(key K) {
+	root, removed := b.ctx.delete(b.root, 0, b.ctx.hash(key), key, b.edit)
+	b.root = root
+	if removed {
+		b.count--
+	}
+}
+
+func (b *adkPersistentMapBuilder[K, V]) Build() PersistentMap[K, V] {
+	// 更换所有权标记，已经返回的版本中的节点不会再被原地修改
+	b.edit = new(hamtEdit)
+	return &adkPersistentMap[K, V]{ctx: b.ctx, root: b.root, count: b.count}
+}
+
+func (c *hamtContext[K, V]) get(n *hamtNode[K, V], key K) (V, bool) {
+	hash := c.hash(key)
+	for shift := uint(0); ; shift += hamtBits {
+		if shift >= 64 {
+			for i := range n.entries {
+				if c.equal(n.entries[i].key, key) {
+					return n.entries[i].val, true
+				}
+			}
+			break
+		}
+		bit := uint32(1) << ((hash >> shift) & hamtMask)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		e := &n.entries[n.index(bit)]
+		if e.child == nil {
+			if e.hash == hash && c.equal(e.key, key) {
+				return e.val, true
+			}
+			break
+		}
+		n = e.child
+	}
+	var zero V
+	return zero, false
+}
+
+// set 返回写入后的节点以及是否新增了 key
+func (c *hamtContext[K, V]) set(n *hamtNode[K, V], shift uint, hash uint64, key K, val V, edit *hamtEdit) (*hamtNode[K, V], bool) {
+	if shift >= 64 {
+		for i := range n.entries {
+			if c.equal(n.entries[i].key, key) {
+				n = n.editable(edit)
+				n.entries[i].val = val
+				return n, false
+			}
+		}
+		n = n.editable(edit)
+		n.entries = append(n.entries, hamtEntry[K, V]{hash: hash, key: key, val: val})
+		return n, true
+	}
+
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	idx := n.index(bit)
+	if n.bitmap&bit == 0 {
+		n = n.editable(edit)
+		n.bitmap |= bit
+		n.entries = append(n.entries, hamtEntry[K, V]{})
+		copy(n.entries[idx+1:], n.entries[idx:])
+		n.entries[idx] = hamtEntry[K, V]{hash: hash, key: key, val: val}
+		return n, true
+	}
+
+	e := n.entries[idx]
+	if e.child != nil {
+		child, added := c.set(e.child, shift+hamtBits, hash, key, val, edit)
+		if child != e.child {
+			n = n.editable(edit)
+			n.entries[idx].child = child
+		}
+		return n, added
+	}
+	if e.hash == hash && c.equal(e.key, key) {
+		n = n.editable(edit)
+		n.entries[idx].val = val
+		return n, false
+	}
+
+	// 与已有的 key 冲突，下沉为子节点
+	child := &hamtNode[K, V]{edit: edit}
+	child, _ = c.set(child, shift+hamtBits, e.hash, e.key, e.val, edit)
+	child, _ = c.set(child, shift+hamtBits, hash, key, val, edit)
+	n = n.editable(edit)
+	n.entries[idx] = hamtEntry[K, V]{child: child}
+	return n, true
+}
+
+// delete 返回删除后的节点以及 key 是否存在
+func (c *hamtContext[K, V]) delete(n *hamtNode[K, V], shift uint, hash uint64, key K, edit *hamtEdit) (*hamtNode[K, V], bool) {
+	if shift >= 64 {
+		for i := range n.entries {
+			if c.equal(n.entries[i].key, key) {
+				n = n.editable(edit)
+				n.entries = append(n.entries[:i], n.entries[i+1:]...)
+				return n, true
+			}
+		}
+		return n, false
+	}
+
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	idx := n.index(bit)
+	e := n.entries[idx]
+	if e.child == nil {
+		if e.hash != hash || !c.equal(e.key, key) {
+			return n, false
+		}
+		n = n.editable(edit)
+		n.removeAt(idx, bit)
+		return n, true
+	}
+
+	child, removed := c.delete(e.child, shift+hamtBits, hash, key, edit)
+	if !removed {
+		return n, false
+	}
+	n = n.editable(edit)
+	switch {
+	case len(child.entries) == 0:
+		n.removeAt(idx, bit)
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// 子节点只剩一个键值对时上提，保持树的紧凑
+		n.entries[idx] = child.entries[0]
+	default:
+		n.entries[idx].child = child
+	}
+	return n, true
+}
+
+// index 返回 bit 对应的分支在 entries 中的下标
+func (n *hamtNode[K, V]) index(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode[K, V]) removeAt(idx int, bit uint32) {
+	n.bitmap &^= bit
+	copy(n.entries[idx:], n.entries[idx+1:])
+	n.entries[len(n.entries)-1] = hamtEntry[K, V]{}
+	n.entries = n.entries[:len(n.entries)-1]
+}
+
+// editable 返回可以原地修改的节点
+// 节点属于当前构建器时直接返回，否则复制一份并归属当前构建器
+func (n *hamtNode[K, V]) editable(edit *hamtEdit) *hamtNode[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	entries := make([]hamtEntry[K, V], len(n.entries), len(n.entries)+1)
+	copy(entries, n.entries)
+	return &hamtNode[K, V]{bitmap: n.bitmap, entries: entries, edit: edit}
+}
+
+func (n *hamtNode[K, V]) forEach(fn func(key K, val V)) {
+	for i := range n.entries {
+		if e := &n.entries[i]; e.child != nil {
+			e.child.forEach(fn)
+		} else {
+			fn(e.key, e.val)
+		}
+	}
+}
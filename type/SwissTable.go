@@ -0,0 +1,227 @@
+package gttype
+
+import (
+	"math/bits"
+)
+
+// swissTable 是 HashMap 的底层存储，采用 Swiss Table 风格的开放寻址：
+// 每 8 个槽位为一组，每组带一个 8 字节的控制字，一个字节对应一个槽位。
+// 控制字节的取值：
+//   - 0x00      空槽位
+//   - 0x7e      墓碑（已删除）
+//   - 0x80~0xff 已占用，低 7 位保存哈希值的 h2 部分
+//
+// 空槽位取 0 使得新分配的表不需要初始化，扩容时不会因为填充控制字产生停顿。
+//
+// 查找时用 h1 定位起始组，再用 SWAR 一次比较整组的 h2，
+// 遇到含空槽位的组即可停止探测。
+
+const (
+	groupSlots = 8
+
+	ctrlEmpty   = 0x00
+	ctrlDeleted = 0x7e
+	ctrlFull    = 0x80
+
+	ctrlLsb        = 0x0101010101010101
+	ctrlMsb        = 0x8080808080808080
+	ctrlAllEmpty   = 0
+	ctrlAllDeleted = 0x7e7e7e7e7e7e7e7e
+
+	defaultLoadFactor = 0.875
+)
+
+// ctrlWord 一个组的控制字
+type ctrlWord uint64
+
+// slotMask 匹配结果，每个被选中的槽位对应字节的最高位为 1
+type slotMask uint64
+
+func (c ctrlWord) matchH2(h2 uint8) slotMask {
+	v := uint64(c) ^ (ctrlLsb * uint64(h2|ctrlFull))
+	return slotMask((v - ctrlLsb) &^ v & ctrlMsb)
+}
+
+func (c ctrlWord) matchEmpty() slotMask {
+	v := uint64(c)
+	return slotMask(^v &^ (v << 6) & ctrlMsb)
+}
+
+func (c ctrlWord) matchEmptyOrDeleted() slotMask {
+	return slotMask(^uint64(c) & ctrlMsb)
+}
+
+func (c ctrlWord) matchFull() slotMask {
+	return slotMask(uint64(c) & ctrlMsb)
+}
+
+func (c ctrlWord) get(i int) uint8 {
+	return uint8(c >> (i * 8))
+}
+
+func (c *ctrlWord) set(i int, b uint8) {
+	shift := i * 8
+	*c = ctrlWord(uint64(*c)&^(0xff<<shift) | uint64(b)<<shift)
+}
+
+// first 返回第一个被选中的槽位下标
+func (m slotMask) first() int {
+	return bits.TrailingZeros64(uint64(m)) >> 3
+}
+
+// next 去掉第一个被选中的槽位
+func (m slotMask) next() slotMask {
+	return m & (m - 1)
+}
+
+// probeSeq 三角探测序列，组数为 2 的幂时可以访问到所有组
+type probeSeq struct {
+	mask   uint64
+	offset uint64
+	index  uint64
+}
+
+func makeProbeSeq(h1, mask uint64) probeSeq {
+	return probeSeq{mask: mask, offset: h1 & mask}
+}
+
+func (s probeSeq) next() probeSeq {
+	s.index++
+	s.offset = (s.offset + s.index) & s.mask
+	return s
+}
+
+func splitHash(hash uint64) (h1 uint64, h2 uint8) {
+	return hash >> 7, uint8(hash & 0x7f)
+}
+
+type swissGroup[K, V any] struct {
+	ctrl ctrlWord
+	keys [groupSlots]K
+	vals [groupSlots]V
+}
+
+type swissTable[K, V any] struct {
+	groups     []swissGroup[K, V]
+	mask       uint64
+	used       int // 已占用的槽位
+	dead       int // 墓碑数量
+	growthLeft int // 在必须重新哈希之前还能消耗的空槽位
+}
+
+// groupsFor 计算容纳 n 个元素所需的组数，结果为 2 的幂
+func groupsFor(n int, loadFactor float64) int {
+	slots := int(float64(n)/loadFactor) + 1
+	groups := (slots + groupSlots - 1) / groupSlots
+	if groups <= 1 {
+		return 1
+	}
+	return 1 << bits.Len(uint(groups-1))
+}
+
+// maxLoad 返回 groups 个组在给定装载因子下最多可以占用的槽位数
+// 至少保留一个空槽位，保证探测一定能终止
+func maxLoad(groups int, loadFactor float64) int {
+	slots := groups * groupSlots
+	n := int(float64(slots) * loadFactor)
+	if n >= slots {
+		n = slots - 1
+	}
+	return n
+}
+
+func newSwissTable[K, V any](groups int, loadFactor float64) *swissTable[K, V] {
+	return &swissTable[K, V]{
+		groups:     make([]swissGroup[K, V], groups),
+		mask:       uint64(groups - 1),
+		growthLeft: maxLoad(groups, loadFactor),
+	}
+}
+
+// find 查找 key 所在的组和槽位
+// 渐进式 rehash 的旧表中可能已没有空槽位，因此最多探测一轮
+func (t *swissTable[K, V]) find(hash uint64, key K, equal func(a, b K) bool) (*swissGroup[K, V], int, bool) {
+	h1, h2 := splitHash(hash)
+	for seq := makeProbeSeq(h1, t.mask); seq.index <= t.mask; seq = seq.next() {
+		g := &t.groups[seq.offset]
+		for m := g.ctrl.matchH2(h2); m != 0; m = m.next() {
+			i := m.first()
+			if equal(g.keys[i], key) {
+				return g, i, true
+			}
+		}
+		if g.ctrl.matchEmpty() != 0 {
+			break
+		}
+	}
+	return nil, 0, false
+}
+
+// insertNew 插入一个确定不存在的 key，调用方需保证 growthLeft > 0
+func (t *swissTable[K, V]) insertNew(hash uint64, key K, val V) {
+	h1, h2 := splitHash(hash)
+	for seq := makeProbeSeq(h1, t.mask); ; seq = seq.next() {
+		g := &t.groups[seq.offset]
+		if m := g.ctrl.matchEmptyOrDeleted(); m != 0 {
+			i := m.first()
+			if g.ctrl.get(i) == ctrlDeleted {
+				t.dead--
+			} else {
+				t.growthLeft--
+			}
+			g.ctrl.set(i, h2|ctrlFull)
+			g.keys[i] = key
+			g.vals[i] = val
+			t.used++
+			return
+		}
+	}
+}
+
+// removeAt 删除组 g 中第 i 个槽位
+func (t *swissTable[K, V]) removeAt(g *swissGroup[K, V], i int) {
+	var zeroK K
+	var zeroV V
+	g.keys[i] = zeroK
+	g.vals[i] = zeroV
+	t.used--
+	// 组内还有空槽位时，探测不会越过该组，可以直接置空
+	if g.ctrl.matchEmpty() != 0 {
+		g.ctrl.set(i, ctrlEmpty)
+		t.growthLeft++
+		return
+	}
+	g.ctrl.set(i, ctrlDeleted)
+	t.dead++
+}
+
+// forEach 按存储顺序遍历所有元素，fn 返回 false 时停止
+func (t *swissTable[K, V]) forEach(fn func(key K, val V) bool) bool {
+	for gi := range t.groups {
+		g := &t.groups[gi]
+		for m := g.ctrl.matchFull(); m != 0; m = m.next() {
+			i := m.first()
+			if !fn(g.keys[i], g.vals[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// scanHome 遍历起始组为 home 的所有元素
+// 这些元素只可能位于从 home 开始的探测序列上，且不会越过第一个含空槽位的组
+func (t *swissTable[K, V]) scanHome(home uint64, hash func(K) uint64, fn func(key K, val V)) {
+	for seq := makeProbeSeq(home, t.mask); seq.index <= t.mask; seq = seq.next() {
+		g := &t.groups[seq.offset]
+		for m := g.ctrl.matchFull(); m != 0; m = m.next() {
+			i := m.first()
+			if h1, _ := splitHash(hash(g.keys[i])); h1&t.mask == home {
+				fn(g.keys[i], g.vals[i])
+			}
+		}
+		if g.ctrl.matchEmpty() != 0 {
+			return
+		}
+	}
+}
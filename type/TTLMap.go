@@ -0,0 +1,323 @@
+package gttype
+
+import (
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// Clock 时间来源，测试时可以注入自定义的实现
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock 使用 time.Now 的时钟
+var SystemClock Clock = systemClock{}
+
+// TTLMap 键值对可以带过期时间的并发安全 map
+// 过期的键值对通过两种方式删除（与 Redis 相同）：
+//   - 惰性删除：访问时发现已过期则删除
+//   - 主动删除：后台定期从带过期时间的 key 中抽样，删除其中已过期的，
+//     过期比例超过 25% 时继续抽样，单次耗时有上限
+type TTLMap[K, V any] interface {
+	// Put 写入不过期的键值对，会清除原有的过期时间
+	Put(key K, val V)
+	// PutWithTTL 写入 ttl 之后过期的键值对，ttl 不大于 0 时等同于 Put
+	PutWithTTL(key K, val V, ttl time.Duration)
+	Get(key K) (V, bool)
+	Remove(key K) bool
+	ContainsKey(key K) bool
+	// Expire 为已存在的 key 设置过期时间
+	Expire(key K, ttl time.Duration) bool
+	// Persist 清除 key 的过期时间
+	Persist(key K) bool
+	// TTL 返回 key 的剩余存活时间，key 存在但不过期时返回 -1
+	TTL(key K) (time.Duration, bool)
+	// Len 元素个数，可能包含已过期但尚未删除的元素
+	Len() int
+	// ForEach 遍历所有未过期的键值对，回调期间持有锁，不能在 fn 中操作该 map
+	ForEach(fn func(key K, val V))
+	// ActiveExpireCycle 立即执行一轮主动过期，返回删除的个数
+	ActiveExpireCycle() int
+	// Close 停止后台的主动过期任务并等待其退出，可以重复调用
+	Close()
+}
+
+// TTLMapOption TTLMap 的可选配置
+type TTLMapOption func(*ttlMapConfig)
+
+type ttlMapConfig struct {
+	clock      Clock
+	interval   time.Duration
+	sampleSize int
+	timeLimit  time.Duration
+}
+
+// WithClock 设置时钟
+func WithClock(clock Clock) TTLMapOption {
+	return func(c *ttlMapConfig) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// WithExpireInterval 设置后台主动过期的间隔，默认 100ms，不大于 0 时不启动后台任务
+func WithExpireInterval(interval time.Duration) TTLMapOption {
+	return func(c *ttlMapConfig) {
+		c.interval = interval
+	}
+}
+
+// WithExpireSample 设置每次抽样的 key 个数，默认 20
+func WithExpireSample(n int) TTLMapOption {
+	return func(c *ttlMapConfig) {
+		if n > 0 {
+			c.sampleSize = n
+		}
+	}
+}
+
+const (
+	defaultExpireInterval = 100 * time.Millisecond
+	defaultExpireSample   = 20
+	// 单轮主动过期最多占用间隔的 25%
+	expireTimeLimitPercent = 25
+	// 抽样中过期比例超过 25% 时继续下一次抽样
+	expireAcceptablePercent = 25
+)
+
+type adkTTLMap[K, V any] struct {
+	mu      sync.Mutex
+	data    *adkHashMap[K, V]
+	expires *adkHashMap[K, int64] // key 到过期时间（UnixNano）
+	cursor  uint64                // 主动过期在 expires 上的 Scan 游标
+	cfg     ttlMapConfig
+
+	stop      chan struct{}
+	done      chan struct{} // 后台任务退出后关闭，未启动时创建即关闭
+	closeOnce sync.Once
+}
+
+// NewTTLMap 创建一个 TTLMap，不再使用时需要调用 Close
+func NewTTLMap[K comparable, V any](opts ...TTLMapOption) TTLMap[K, V] {
+	return NewTTLMapWith[K, V](ComparableHasher[K]{}, opts...)
+}
+
+// NewTTLMapWith 使用自定义的 Hasher 创建 TTLMap
+func NewTTLMapWith[K, V any](hasher Hasher[K], opts ...TTLMapOption) TTLMap[K, V] {
+	cfg := ttlMapConfig{
+		clock:      SystemClock,
+		interval:   defaultExpireInterval,
+		sampleSize: defaultExpireSample,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.timeLimit = cfg.interval * expireTimeLimitPercent / 100
+
+	hash := seededHash(hasher, maphash.MakeSeed())
+	mapCfg := hashMapConfig{loadFactor: defaultLoadFactor}
+	m := &adkTTLMap[K, V]{
+		data:    newAdkHashMap[K, V](hash, hasher.Equal, mapCfg),
+		expires: newAdkHashMap[K, int64](hash, hasher.Equal, mapCfg),
+		cfg:     cfg,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if cfg.interval > 0 {
+		go m.run()
+	} else {
+		close(m.done)
+	}
+	return m
+}
+
+func (m *adkTTLMap[K, V]) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.cfg.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.ActiveExpireCycle()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+func (m *adkTTLMap[K, V]) Close() {
+	m.closeOnce.Do(func() {
+		close(m.stop)
+	})
+	<-m.done
+}
+
+func (m *adkTTLMap[K, V]) Put(key K, val V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash := m.data.hash(key)
+	m.data.put(hash, key, val)
+	m.expires.remove(hash, key)
+}
+
+func (m *adkTTLMap[K, V]) PutWithTTL(key K, val V, ttl time.Duration) {
+	if ttl <= 0 {
+		m.Put(key, val)
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash := m.data.hash(key)
+	m.data.put(hash, key, val)
+	m.expires.put(hash, key, m.cfg.clock.Now().Add(ttl).UnixNano())
+}
+
+func (m *adkTTLMap[K, V]) Get(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash := m.data.hash(key)
+	if m.expireIfNeeded(hash, key, m.cfg.clock.Now().UnixNano()) {
+		var zero V
+		return zero, false
+	}
+	return m.data.get(hash, key)
+}
+
+func (m *adkTTLMap[K, V]) Remove(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash := m.data.hash(key)
+	if m.expireIfNeeded(hash, key, m.cfg.clock.Now().UnixNano()) {
+		return false
+	}
+	m.expires.remove(hash, key)
+	return m.data.remove(hash, key)
+}
+
+func (m *adkTTLMap[K, V]) ContainsKey(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+func (m *adkTTLMap[K, V]) Expire(key K, ttl time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash := m.data.hash(key)
+	now := m.cfg.clock.Now()
+	if m.expireIfNeeded(hash, key, now.UnixNano()) {
+		return false
+	}
+	if _, ok := m.data.get(hash, key); !ok {
+		return false
+	}
+	if ttl <= 0 {
+		// 与 Redis 相同，非正数的过期时间直接删除
+		m.data.remove(hash, key)
+		m.expires.remove(hash, key)
+		return true
+	}
+	m.expires.put(hash, key, now.Add(ttl).UnixNano())
+	return true
+}
+
+func (m *adkTTLMap[K, V]) Persist(key K) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash := m.data.hash(key)
+	if m.expireIfNeeded(hash, key, m.cfg.clock.Now().UnixNano()) {
+		return false
+	}
+	return m.expires.remove(hash, key)
+}
+
+func (m *adkTTLMap[K, V]) TTL(key K) (time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	hash := m.data.hash(key)
+	now := m.cfg.clock.Now().UnixNano()
+	if m.expireIfNeeded(hash, key, now) {
+		return 0, false
+	}
+	if _, ok := m.data.get(hash, key); !ok {
+		return 0, false
+	}
+	at, ok := m.expires.get(hash, key)
+	if !ok {
+		return -1, true
+	}
+	return time.Duration(at - now), true
+}
+
+func (m *adkTTLMap[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data.Len()
+}
+
+func (m *adkTTLMap[K, V]) ForEach(fn func(key K, val V)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.cfg.clock.Now().UnixNano()
+	m.data.forEach(func(key K, val V) bool {
+		if at, ok := m.expires.Get(key); !ok || at > now {
+			fn(key, val)
+		}
+		return true
+	})
+}
+
+// expireIfNeeded key 已过期时删除，返回是否已过期
+func (m *adkTTLMap[K, V]) expireIfNeeded(hash uint64, key K, now int64) bool {
+	at, ok := m.expires.get(hash, key)
+	if !ok || at > now {
+		return false
+	}
+	m.data.remove(hash, key)
+	m.expires.remove(hash, key)
+	return true
+}
+
+func (m *adkTTLMap[K, V]) ActiveExpireCycle() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	start := m.cfg.clock.Now()
+	removed := 0
+	var sampled []Entry[K, int64]
+	for m.expires.Len() > 0 {
+		// 沿游标继续抽样，下一轮从上次停下的位置开始
+		sampled = sampled[:0]
+		m.cursor = m.expires.scan(m.cursor, m.cfg.sampleSize, func(key K, at int64) {
+			sampled = append(sampled, Entry[K, int64]{Key: key, Value: at})
+		})
+
+		now := m.cfg.clock.Now()
+		expired := 0
+		for _, e := range sampled {
+			if e.Value <= now.UnixNano() {
+				hash := m.data.hash(e.Key)
+				if m.expires.remove(hash, e.Key) {
+					m.data.remove(hash, e.Key)
+					expired++
+				}
+			}
+		}
+		removed += expired
+
+		if len(sampled) > 0 && expired*100 <= len(sampled)*expireAcceptablePercent {
+			break
+		}
+		if m.cfg.timeLimit > 0 && now.Sub(start) > m.cfg.timeLimit {
+			break
+		}
+	}
+	return removed
+}
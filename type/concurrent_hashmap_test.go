@@ -0,0 +1,82 @@
+package gttype
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentHashMap_Compute(t *testing.T) {
+	m := NewConcurrentHashMap[int, int](WithShards(8))
+	const workers, rounds = 8, 1000
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				key := i % 100
+				m.Merge(key, 1, func(old, val int) int { return old + val })
+				m.Compute(-1, func(old int, _ bool) (int, bool) { return old + 1, true })
+				m.ComputeIfAbsent(key+1000, func() int { return key })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := m.Get(-1); v != workers*rounds {
+		t.Fatalf("Compute 计数 = %d, want %d", v, workers*rounds)
+	}
+	for key := 0; key < 100; key++ {
+		if v, _ := m.Get(key); v != workers*rounds/100 {
+			t.Fatalf("Merge(%d) = %d, want %d", key, v, workers*rounds/100)
+		}
+		if v, _ := m.Get(key + 1000); v != key {
+			t.Fatalf("ComputeIfAbsent(%d) = %d", key+1000, v)
+		}
+	}
+	if m.Len() != 201 {
+		t.Fatalf("len = %d, want 201", m.Len())
+	}
+
+	if v, ok := m.Compute(-1, func(int, bool) (int, bool) { return 0, false }); ok || v != 0 || m.ContainsKey(-1) {
+		t.Fatal("Compute 返回不保留时应删除 key")
+	}
+	if v, loaded := m.LoadOrStore(1000, 7); !loaded || v != 0 {
+		t.Fatalf("LoadOrStore = %d, %v", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore(-2, 7); loaded || v != 7 {
+		t.Fatalf("LoadOrStore = %d, %v", v, loaded)
+	}
+
+	// Range 回调中修改 map 不会死锁
+	m.Range(func(key, _ int) bool {
+		m.Remove(key)
+		return true
+	})
+	if m.Len() != 0 {
+		t.Fatalf("len = %d, want 0", m.Len())
+	}
+}
+
+func TestConcurrentHashMap_Scan(t *testing.T) {
+	m := NewConcurrentHashMap[int, int](WithShards(4))
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i)
+	}
+	seen := make(map[int]bool)
+	cursor := uint64(0)
+	for {
+		var items []Entry[int, int]
+		cursor, items = m.Scan(cursor, 10)
+		for _, e := range items {
+			seen[e.Key] = true
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 1000 {
+		t.Fatalf("Scan 返回 %d 个元素, want 1000", len(seen))
+	}
+}
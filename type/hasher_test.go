@@ -0,0 +1,49 @@
+package gttype
+
+import (
+	"hash/maphash"
+	"math"
+	"testing"
+)
+
+func TestHasher_NonComparableKeys(t *testing.T) {
+	bs := NewHashSetWith[[]byte](BytesHasher{})
+	bs.Add([]byte("abc")).Add([]byte("abc")).Add([]byte("abd"))
+	if bs.Size() != 2 {
+		t.Fatalf("BytesHasher size = %d, want 2", bs.Size())
+	}
+
+	ids := NewHashMapWith[[]int, string](SliceHasher[int]{})
+	ids.Put([]int{1, 2}, "a")
+	ids.Put([]int{1, 2}, "b")
+	ids.Put([]int{2, 1}, "c")
+	if v, ok := ids.Get([]int{1, 2}); !ok || v != "b" || ids.Len() != 2 {
+		t.Fatalf("SliceHasher Get = %v, %v, len = %d", v, ok, ids.Len())
+	}
+
+	type key struct {
+		Parts []string
+		Attrs map[string]int
+		next  *int
+	}
+	one, alsoOne := 1, 1
+	ks := NewHashSetWith[key](StructHasher[key]{})
+	ks.Add(key{Parts: []string{"a", "b"}, Attrs: map[string]int{"x": 1, "y": 2}, next: &one})
+	ks.Add(key{Parts: []string{"a", "b"}, Attrs: map[string]int{"y": 2, "x": 1}, next: &alsoOne})
+	ks.Add(key{Parts: []string{"a"}})
+	if ks.Size() != 2 {
+		t.Fatalf("StructHasher size = %d, want 2", ks.Size())
+	}
+	if !ks.Remove(key{Parts: []string{"a"}}) {
+		t.Fatal("StructHasher Remove 失败")
+	}
+}
+
+func TestStructHasher_NegativeZero(t *testing.T) {
+	type point struct{ X, Y float64 }
+	h := StructHasher[point]{}
+	seed := maphash.MakeSeed()
+	if h.Hash(seed, point{}) != h.Hash(seed, point{X: math.Copysign(0, -1)}) {
+		t.Fatal("-0 与 0 的哈希值应相同")
+	}
+}
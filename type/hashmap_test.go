@@ -0,0 +1,287 @@
+package gttype
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHashMap_Basic(t *testing.T) {
+	m := NewHashMap[string, int]()
+	m.Put("a", 1)
+	m.Put("b", 2)
+	m.Put("a", 3)
+	if m.Len() != 2 {
+		t.Fatalf("len = %d, want 2", m.Len())
+	}
+	if v, ok := m.Get("a"); !ok || v != 3 {
+		t.Fatalf("Get(a) = %v, %v", v, ok)
+	}
+	if !m.ContainsKey("b") || m.ContainsKey("c") {
+		t.Fatal("ContainsKey 结果错误")
+	}
+	if !m.Remove("b") || m.Remove("b") {
+		t.Fatal("Remove 结果错误")
+	}
+	if len(m.Keys()) != 1 || len(m.Values()) != 1 {
+		t.Fatal("Keys/Values 数量错误")
+	}
+	m.Clear()
+	if m.Len() != 0 || m.ContainsKey("a") {
+		t.Fatal("Clear 后仍有数据")
+	}
+}
+
+// TestHashMap_Random 与内置 map 对比随机操作的结果
+func TestHashMap_Random(t *testing.T) {
+	for _, opts := range [][]HashMapOption{
+		nil,
+		{WithLoadFactor(0.5)},
+		{WithInitialCapacity(1000), WithLoadFactor(0.95)},
+		{WithIncrementalRehash(1)},
+		{WithIncrementalRehash(4), WithLoadFactor(0.5)},
+	} {
+		m := NewHashMap[int, int](opts...)
+		want := make(map[int]int)
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 100000; i++ {
+			k := r.Intn(5000)
+			switch r.Intn(3) {
+			case 0, 1:
+				m.Put(k, i)
+				want[k] = i
+			case 2:
+				_, ok := want[k]
+				if m.Remove(k) != ok {
+					t.Fatalf("Remove(%d) 结果错误", k)
+				}
+				delete(want, k)
+			}
+		}
+		if m.Len() != len(want) {
+			t.Fatalf("len = %d, want %d", m.Len(), len(want))
+		}
+		for k, v := range want {
+			if got, ok := m.Get(k); !ok || got != v {
+				t.Fatalf("Get(%d) = %v, %v, want %v", k, got, ok, v)
+			}
+		}
+		count := 0
+		m.ForEach(func(k, v int) {
+			if want[k] != v {
+				t.Fatalf("ForEach(%d) = %v, want %v", k, v, want[k])
+			}
+			count++
+		})
+		if count != len(want) {
+			t.Fatalf("ForEach 遍历了 %d 个元素, want %d", count, len(want))
+		}
+	}
+}
+
+func TestHashMap_IncrementalRehash(t *testing.T) {
+	m := NewHashMap[int, int](WithIncrementalRehash(1)).(*adkHashMap[int, int])
+	rehashing := 0
+	for i := 0; i < 10000; i++ {
+		m.Put(i, i)
+		if m.old != nil {
+			rehashing++
+		}
+		if m.Len() != i+1 {
+			t.Fatalf("len = %d, want %d", m.Len(), i+1)
+		}
+	}
+	if rehashing == 0 {
+		t.Fatal("没有进入渐进式 rehash")
+	}
+	for i := 0; i < 10000; i++ {
+		if v, ok := m.Get(i); !ok || v != i {
+			t.Fatalf("Get(%d) = %v, %v", i, v, ok)
+		}
+	}
+	if len(m.Keys()) != 10000 {
+		t.Fatalf("Keys 数量 = %d", len(m.Keys()))
+	}
+}
+
+const benchSize = 1 << 16
+
+func benchKeys() []string {
+	keys := make([]string, benchSize)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys
+}
+
+func BenchmarkHashMap_Put(b *testing.B) {
+	keys := benchKeys()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := NewHashMap[string, int]()
+		for j, k := range keys {
+			m.Put(k, j)
+		}
+	}
+}
+
+func BenchmarkBuiltinMap_Put(b *testing.B) {
+	keys := benchKeys()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m := make(map[string]int)
+		for j, k := range keys {
+			m[k] = j
+		}
+	}
+}
+
+func BenchmarkHashMap_Get(b *testing.B) {
+	keys := benchKeys()
+	m := NewHashMap[string, int](WithInitialCapacity(benchSize))
+	for j, k := range keys {
+		m.Put(k, j)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(keys[i&(benchSize-1)])
+	}
+}
+
+func BenchmarkBuiltinMap_Get(b *testing.B) {
+	keys := benchKeys()
+	m := make(map[string]int, benchSize)
+	for j, k := range keys {
+		m[k] = j
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = m[keys[i&(benchSize-1)]]
+	}
+}
+
+// benchmarkPutPause 统计单次 Put 的最大耗时
+// 一次性 rehash 的最大停顿随元素个数线性增长，渐进式 rehash 的停顿是有界的
+func benchmarkPutPause(b *testing.B, opts ...HashMapOption) {
+	var maxPause time.Duration
+	for i := 0; i < b.N; i++ {
+		m := NewHashMap[int, int](opts...)
+		for j := 0; j < 1<<20; j++ {
+			start := time.Now()
+			m.Put(j, j)
+			if d := time.Since(start); d > maxPause {
+				maxPause = d
+			}
+		}
+	}
+	b.ReportMetric(float64(maxPause.Nanoseconds()), "max-pause-ns")
+}
+
+func BenchmarkHashMap_PutPause(b *testing.B) {
+	benchmarkPutPause(b)
+}
+
+func BenchmarkHashMap_PutPauseIncremental(b *testing.B) {
+	benchmarkPutPause(b, WithIncrementalRehash(1))
+}
+
+// TestHashMap_Scan 遍历过程中持续写入触发扩容，初始元素都应至少返回一次
+func TestHashMap_Scan(t *testing.T) {
+	for _, opts := range [][]HashMapOption{nil, {WithIncrementalRehash(1)}} {
+		m := NewHashMap[int, int](opts...)
+		for i := 0; i < 1000; i++ {
+			m.Put(i, i)
+		}
+		seen := make(map[int]bool)
+		next := 1000
+		cursor := uint64(0)
+		for {
+			var items []Entry[int, int]
+			cursor, items = m.Scan(cursor, 7)
+			for _, e := range items {
+				seen[e.Key] = true
+			}
+			for i := 0; i < 50; i++ {
+				m.Put(next, next)
+				next++
+			}
+			if cursor == 0 {
+				break
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			if !seen[i] {
+				t.Fatalf("Scan 没有返回 %d", i)
+			}
+		}
+	}
+}
+
+// TestHashMap_ScanShrink 遍历期间大量删除使表缩容，始终存在的元素仍然至少返回一次
+func TestHashMap_ScanShrink(t *testing.T) {
+	for _, opts := range [][]HashMapOption{nil, {WithIncrementalRehash(1)}} {
+		m := NewHashMap[int, int](opts...)
+		for i := 0; i < 9000; i++ {
+			m.Put(i, i)
+		}
+		before := len(m.(*adkHashMap[int, int]).table.groups)
+		seen := make(map[int]bool)
+		next := 1000
+		cursor := uint64(0)
+		for {
+			var items []Entry[int, int]
+			cursor, items = m.Scan(cursor, 7)
+			for _, e := range items {
+				seen[e.Key] = true
+			}
+			for i := 0; i < 400 && next < 9000; i++ {
+				m.Remove(next)
+				next++
+			}
+			if cursor == 0 {
+				break
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			if !seen[i] {
+				t.Fatalf("Scan 没有返回 %d", i)
+			}
+		}
+		if m.Len() != 1000 {
+			t.Fatalf("Len() = %d, want 1000", m.Len())
+		}
+		if after := len(m.(*adkHashMap[int, int]).table.groups); after >= before {
+			t.Fatalf("删除后没有缩容: %d -> %d 组", before, after)
+		}
+		for i := 0; i < 1000; i++ {
+			if v, ok := m.Get(i); !ok || v != i {
+				t.Fatalf("Get(%d) = %d, %v", i, v, ok)
+			}
+		}
+	}
+}
+
+func TestHashSet_Scan(t *testing.T) {
+	s := NewHashSet[string]()
+	for i := 0; i < 500; i++ {
+		s.Add(strconv.Itoa(i))
+	}
+	seen := make(map[string]bool)
+	cursor := uint64(0)
+	for {
+		var items []string
+		cursor, items = s.Scan(cursor, 20)
+		for _, v := range items {
+			seen[v] = true
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 500 {
+		t.Fatalf("Scan 返回 %d 个元素, want 500", len(seen))
+	}
+}
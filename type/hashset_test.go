@@ -0,0 +1,70 @@
+package gttype
+
+import (
+	"slices"
+	"testing"
+)
+
+func newIntSet(vals ...int) HashSet[int] {
+	s := NewHashSet[int]()
+	for _, v := range vals {
+		s.Add(v)
+	}
+	return s
+}
+
+func sortedData(s HashSet[int]) []int {
+	data := s.GetData()
+	slices.Sort(data)
+	return data
+}
+
+func TestHashSet_Algebra(t *testing.T) {
+	a := newIntSet(1, 2, 3, 4)
+	b := newIntSet(3, 4, 5)
+
+	cases := []struct {
+		name string
+		got  HashSet[int]
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersect", a.Intersect(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+	}
+	for _, c := range cases {
+		if got := sortedData(c.got); !slices.Equal(got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, got, c.want)
+		}
+	}
+	if a.Size() != 4 || b.Size() != 3 {
+		t.Fatal("集合运算不应修改原集合")
+	}
+
+	if !newIntSet(3, 4).IsSubsetOf(a) || a.IsSubsetOf(b) {
+		t.Error("IsSubsetOf 结果错误")
+	}
+	if !a.IsSupersetOf(newIntSet(1, 2)) || b.IsSupersetOf(a) {
+		t.Error("IsSupersetOf 结果错误")
+	}
+	if a.IsDisjoint(b) || !a.IsDisjoint(newIntSet(7, 8)) {
+		t.Error("IsDisjoint 结果错误")
+	}
+	if !a.Equal(newIntSet(4, 3, 2, 1)) || a.Equal(b) || !a.Contains(1) || a.Contains(5) {
+		t.Error("Equal/Contains 结果错误")
+	}
+}
+
+func TestHashSet_Remove(t *testing.T) {
+	s := newIntSet(1, 2, 3)
+	if !s.Remove(2) {
+		t.Error("Remove 已存在的元素应返回 true")
+	}
+	if s.Remove(2) || s.Remove(10) {
+		t.Error("Remove 不存在的元素应返回 false")
+	}
+	if got := sortedData(s); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Remove 后 = %v, want [1 3]", got)
+	}
+}
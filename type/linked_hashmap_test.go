@@ -0,0 +1,81 @@
+package gttype
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestLinkedHashMap_InsertionOrder(t *testing.T) {
+	m := NewLinkedHashMap[string, int]()
+	for i, k := range []string{"c", "a", "b"} {
+		m.Put(k, i)
+	}
+	m.Put("a", 10)
+	m.Get("c")
+	if keys := m.Keys(); !slices.Equal(keys, []string{"c", "a", "b"}) {
+		t.Fatalf("Keys = %v", keys)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"c":0,"a":10,"b":2}` {
+		t.Fatalf("MarshalJSON = %s", data)
+	}
+
+	other := NewLinkedHashMap[string, int]()
+	if err = json.Unmarshal([]byte(`{"z":1,"y":2,"x":3}`), other); err != nil {
+		t.Fatal(err)
+	}
+	if keys := other.Keys(); !slices.Equal(keys, []string{"z", "y", "x"}) {
+		t.Fatalf("UnmarshalJSON 顺序 = %v", keys)
+	}
+
+	ints := NewLinkedHashMap[int, bool]()
+	ints.Put(2, true)
+	ints.Put(1, false)
+	if data, _ = json.Marshal(ints); string(data) != `{"2":true,"1":false}` {
+		t.Fatalf("MarshalJSON = %s", data)
+	}
+	back := NewLinkedHashMap[int, bool]()
+	if err = json.Unmarshal(data, back); err != nil || !slices.Equal(back.Keys(), []int{2, 1}) {
+		t.Fatalf("UnmarshalJSON = %v, %v", back.Keys(), err)
+	}
+}
+
+func TestLinkedHashMap_AccessOrderLRU(t *testing.T) {
+	m := NewLinkedHashMap[int, int](WithAccessOrder())
+	var evicted []int
+	m.SetRemoveEldest(func(key, _ int) bool {
+		if m.Len() > 3 {
+			evicted = append(evicted, key)
+			return true
+		}
+		return false
+	})
+	m.Put(1, 1)
+	m.Put(2, 2)
+	m.Put(3, 3)
+	m.Get(1)
+	m.Peek(2)
+	m.Put(4, 4)
+	m.Put(5, 5)
+
+	if !slices.Equal(evicted, []int{2, 3}) {
+		t.Fatalf("淘汰顺序 = %v", evicted)
+	}
+	if keys := m.Keys(); !slices.Equal(keys, []int{1, 4, 5}) {
+		t.Fatalf("Keys = %v", keys)
+	}
+	if k, _, _ := m.Eldest(); k != 1 {
+		t.Fatalf("Eldest = %d", k)
+	}
+	if k, _, _ := m.RemoveEldest(); k != 1 || m.Len() != 2 {
+		t.Fatalf("RemoveEldest = %d, len = %d", k, m.Len())
+	}
+	if k, _, _ := m.Newest(); k != 5 {
+		t.Fatalf("Newest = %d", k)
+	}
+}
@@ -0,0 +1,93 @@
+package gttype
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMultiMap(t *testing.T) {
+	list := NewListMultiMap[string, int]()
+	list.Put("a", 1)
+	list.Put("a", 1)
+	list.Put("b", 2)
+	list.Put("a", 3)
+	if list.KeyCount() != 2 || list.ValueCount() != 4 {
+		t.Fatalf("KeyCount = %d, ValueCount = %d", list.KeyCount(), list.ValueCount())
+	}
+	if got := list.GetAll("a"); !slices.Equal(got, []int{1, 1, 3}) {
+		t.Fatalf("GetAll(a) = %v", got)
+	}
+	if !list.RemoveValue("a", 1) || !list.ContainsEntry("a", 1) {
+		t.Fatal("RemoveValue 只应删除一个 value")
+	}
+	data, err := json.Marshal(list)
+	if err != nil || string(data) != `{"a":[1,3],"b":[2]}` {
+		t.Fatalf("MarshalJSON = %s, %v", data, err)
+	}
+	if got := list.RemoveAll("a"); !slices.Equal(got, []int{1, 3}) || list.ValueCount() != 1 {
+		t.Fatalf("RemoveAll(a) = %v, ValueCount = %d", got, list.ValueCount())
+	}
+	if !list.RemoveValue("b", 2) || list.ContainsKey("b") {
+		t.Fatal("最后一个 value 被删除后 key 也应被删除")
+	}
+
+	set := NewSetMultiMap[string, int]()
+	if err = json.Unmarshal([]byte(`{"x":[3,1,3],"y":[2]}`), set); err != nil {
+		t.Fatal(err)
+	}
+	if got := set.GetAll("x"); !slices.Equal(got, []int{3, 1}) || set.ValueCount() != 3 {
+		t.Fatalf("GetAll(x) = %v, ValueCount = %d", got, set.ValueCount())
+	}
+	if set.Put("x", 1) {
+		t.Fatal("集合桶不应添加重复的 value")
+	}
+}
+
+func TestBiMap(t *testing.T) {
+	m := NewBiMap[string, int]()
+	if err := m.Put("a", 1); err != nil {
+		t.Fatal(err)
+	}
+	m.Put("b", 2)
+	if err := m.Put("c", 1); !errors.Is(err, ErrValueExists) {
+		t.Fatalf("Put 重复 value 应返回 ErrValueExists, got %v", err)
+	}
+	m.Put("a", 3)
+	if m.ContainsValue(1) {
+		t.Fatal("覆盖 key 后旧的 value 应被删除")
+	}
+
+	inv := m.Inverse()
+	if k, ok := inv.Get(3); !ok || k != "a" {
+		t.Fatalf("Inverse.Get(3) = %v, %v", k, ok)
+	}
+	inv.ForcePut(2, "c")
+	if m.ContainsKey("b") || !m.ContainsKey("c") || m.Len() != 2 {
+		t.Fatalf("ForcePut 之后 keys = %v", m.Keys())
+	}
+	if inv.Inverse() != m {
+		t.Fatal("Inverse 的 Inverse 应是自身")
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil || string(data) != `{"a":3,"c":2}` {
+		t.Fatalf("MarshalJSON = %s, %v", data, err)
+	}
+	if err = json.Unmarshal([]byte(`{"x":1,"y":1}`), m); !errors.Is(err, ErrValueExists) {
+		t.Fatalf("UnmarshalJSON 重复 value 应返回 ErrValueExists, got %v", err)
+	}
+	if data, _ = json.Marshal(m); string(data) != `{"a":3,"c":2}` || !inv.ContainsKey(3) || inv.ContainsKey(1) {
+		t.Fatalf("UnmarshalJSON 失败后内容应保持不变, got %s", data)
+	}
+	if err = json.Unmarshal([]byte(`{"x":1,"y":2}`), m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ContainsKey("a") || m.Len() != 2 {
+		t.Fatalf("UnmarshalJSON 应替换原有内容, keys = %v", m.Keys())
+	}
+	if k, ok := inv.Get(2); !ok || k != "y" {
+		t.Fatalf("UnmarshalJSON 之后 Inverse.Get(2) = %v, %v", k, ok)
+	}
+}
@@ -0,0 +1,84 @@
+package gttype
+
+import (
+	"hash/maphash"
+	"math/rand"
+	"testing"
+)
+
+func TestPersistentMap_Versions(t *testing.T) {
+	v0 := NewPersistentMap[string, int]()
+	v1 := v0.Set("a", 1)
+	v2 := v1.Set("b", 2).Set("a", 3)
+	v3 := v2.Delete("b")
+
+	if v0.Len() != 0 || v1.Len() != 1 || v2.Len() != 2 || v3.Len() != 1 {
+		t.Fatalf("len = %d %d %d %d", v0.Len(), v1.Len(), v2.Len(), v3.Len())
+	}
+	if v, _ := v1.Get("a"); v != 1 {
+		t.Fatalf("旧版本被修改: a = %d", v)
+	}
+	if v, _ := v2.Get("a"); v != 3 {
+		t.Fatalf("a = %d, want 3", v)
+	}
+	if v3.ContainsKey("b") || !v2.ContainsKey("b") {
+		t.Fatal("Delete 影响了旧版本")
+	}
+	if v3.Delete("missing") != v3 {
+		t.Fatal("删除不存在的 key 应返回当前版本")
+	}
+}
+
+// collidingHasher 所有 key 的哈希值相同，用于测试冲突节点
+type collidingHasher struct{}
+
+func (collidingHasher) Hash(maphash.Seed, int) uint64 { return 42 }
+func (collidingHasher) Equal(a, b int) bool           { return a == b }
+
+func TestPersistentMap_Random(t *testing.T) {
+	for _, empty := range []PersistentMap[int, int]{
+		NewPersistentMap[int, int](),
+		NewPersistentMapWith[int, int](collidingHasher{}),
+	} {
+		r := rand.New(rand.NewSource(1))
+		want := make(map[int]int)
+		m := empty
+		b := empty.Builder()
+		for i := 0; i < 20000; i++ {
+			k := r.Intn(2000)
+			if r.Intn(3) == 0 {
+				m = m.Delete(k)
+				b.Delete(k)
+				delete(want, k)
+			} else {
+				m = m.Set(k, i)
+				b.Set(k, i)
+				want[k] = i
+			}
+			if i%5000 == 0 {
+				// 中途 Build 之后继续写入，不能影响已返回的版本
+				snapshot := b.Build()
+				size := snapshot.Len()
+				b.Set(-1, -1)
+				b.Delete(-1)
+				if snapshot.Len() != size || snapshot.ContainsKey(-1) {
+					t.Fatal("Build 返回的版本被修改")
+				}
+			}
+		}
+		built := b.Build()
+		for _, got := range []PersistentMap[int, int]{m, built} {
+			if got.Len() != len(want) {
+				t.Fatalf("len = %d, want %d", got.Len(), len(want))
+			}
+			for k, v := range want {
+				if g, ok := got.Get(k); !ok || g != v {
+					t.Fatalf("Get(%d) = %v, %v, want %v", k, g, ok, v)
+				}
+			}
+			if len(got.Keys()) != len(want) {
+				t.Fatalf("Keys 数量 = %d", len(got.Keys()))
+			}
+		}
+	}
+}
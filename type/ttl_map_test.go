@@ -0,0 +1,142 @@
+package gttype
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestTTLMap_LazyExpire(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := NewTTLMap[string, int](WithClock(clock), WithExpireInterval(0))
+	defer m.Close()
+
+	m.PutWithTTL("a", 1, time.Second)
+	m.Put("b", 2)
+	if ttl, ok := m.TTL("a"); !ok || ttl != time.Second {
+		t.Fatalf("TTL(a) = %v, %v", ttl, ok)
+	}
+	if ttl, ok := m.TTL("b"); !ok || ttl != -1 {
+		t.Fatalf("TTL(b) = %v, %v", ttl, ok)
+	}
+
+	clock.Advance(999 * time.Millisecond)
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Fatalf("未过期时 Get(a) = %v, %v", v, ok)
+	}
+	clock.Advance(time.Millisecond)
+	if _, ok := m.Get("a"); ok {
+		t.Fatal("过期后仍能读到 a")
+	}
+	if m.Len() != 1 {
+		t.Fatalf("惰性删除后 len = %d, want 1", m.Len())
+	}
+
+	m.Expire("b", time.Second)
+	m.Persist("b")
+	clock.Advance(time.Hour)
+	if !m.ContainsKey("b") {
+		t.Fatal("Persist 之后 b 不应过期")
+	}
+}
+
+func TestTTLMap_ActiveExpire(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	m := NewTTLMap[int, int](WithClock(clock), WithExpireInterval(0))
+	defer m.Close()
+
+	for i := 0; i < 1000; i++ {
+		m.PutWithTTL(i, i, time.Second)
+	}
+	for i := 1000; i < 1100; i++ {
+		m.PutWithTTL(i, i, time.Hour)
+	}
+	clock.Advance(2 * time.Second)
+
+	removed := m.ActiveExpireCycle()
+	// 过期比例降到 25% 以下才会停止，绝大部分过期 key 应被删除
+	if removed < 900 {
+		t.Fatalf("主动过期删除了 %d 个, 期望至少 900 个", removed)
+	}
+	count := 0
+	m.ForEach(func(key, _ int) {
+		if key < 1000 {
+			t.Fatalf("ForEach 返回了已过期的 %d", key)
+		}
+		count++
+	})
+	if count != 100 {
+		t.Fatalf("ForEach 返回 %d 个, want 100", count)
+	}
+}
+
+func TestTTLMap_Background(t *testing.T) {
+	m := NewTTLMap[int, int](WithExpireInterval(time.Millisecond))
+	defer m.Close()
+	for i := 0; i < 100; i++ {
+		m.PutWithTTL(i, i, time.Millisecond)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if m.Len() != 0 {
+		t.Fatalf("后台主动过期后 len = %d", m.Len())
+	}
+}
+
+// countingClock 记录 Now 的调用次数
+type countingClock struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return time.Unix(0, 0)
+}
+
+func (c *countingClock) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func TestTTLMap_CloseWaits(t *testing.T) {
+	clock := &countingClock{}
+	m := NewTTLMap[int, int](WithClock(clock), WithExpireInterval(time.Millisecond))
+	m.PutWithTTL(1, 1, time.Hour)
+	for clock.Calls() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Close 返回后后台任务已经退出，不会再访问 map
+	m.Close()
+	calls := clock.Calls()
+	time.Sleep(10 * time.Millisecond)
+	if clock.Calls() != calls {
+		t.Fatal("Close 返回后后台任务仍在运行")
+	}
+	m.Close()
+
+	NewTTLMap[int, int](WithExpireInterval(0)).Close()
+}